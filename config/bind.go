@@ -0,0 +1,207 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sentinez/shared/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Prefix is the environment variable prefix of the service configuration.
+const Prefix = "SENZ"
+
+// listSep separates the elements of a repeated field.
+const listSep = ","
+
+// lookupFunc resolves the raw value of an environment variable.
+type lookupFunc func(key string) (string, bool)
+
+// Bind populates msg from environment variables derived from its fields.
+// Each field maps to PREFIX_FIELD_NAME, e.g. timescale_uri with prefix
+// SENZ reads SENZ_TIMESCALE_URI. Nested messages extend the prefix with
+// their field name. Repeated fields are comma-separated, enums are read
+// by name or number and durations use time.ParseDuration syntax.
+// Unset variables leave the field untouched and empty ones clear it, so
+// that a default can be overridden with a zero value. A variable can
+// instead be read from the file named by its _FILE variant, e.g.
+// SENZ_SECRET_KEY_FILE, and values may reference other variables as
+// ${VAR}.
+func Bind(msg proto.Message, prefix string) error {
+	b := &binder{lookup: os.LookupEnv}
+	return b.bind(msg.ProtoReflect(), prefix, "")
+}
+
+// EnvName returns the environment variable name bound to fd.
+func EnvName(prefix string, fd protoreflect.FieldDescriptor) string {
+	return joinEnv(prefix, string(fd.Name()))
+}
+
+func joinEnv(prefix, name string) string {
+	if prefix == "" {
+		return strings.ToUpper(name)
+	}
+
+	return strings.ToUpper(prefix + "_" + name)
+}
+
+// binder walks a message and fills its fields from lookup.
+type binder struct {
+	lookup lookupFunc
+
+	// path holds the message types being bound, guarding against
+	// recursive message definitions.
+	path []protoreflect.FullName
+
+	// set collects the dotted paths of the fields bound.
+	set []string
+
+	// verbatim leaves ${VAR} references alone, for values that their
+	// source has already expanded.
+	verbatim bool
+}
+
+// bind fills m, at the dotted field path at, from the variables under
+// prefix.
+func (b *binder) bind(m protoreflect.Message, prefix, at string) error {
+	name := m.Descriptor().FullName()
+	if slices.Contains(b.path, name) {
+		return nil
+	}
+
+	b.path = append(b.path, name)
+	defer func() { b.path = b.path[:len(b.path)-1] }()
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		err := b.bindField(m, fd, EnvName(prefix, fd), fieldPath(at, fd))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *binder) bindField(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor, key, path string) error {
+	switch {
+	case fd.IsMap():
+		return nil
+	case isNested(fd):
+		return b.bindNested(m, fd, key, path)
+	}
+
+	raw, ok, err := resolve(b.lookup, key)
+	if err != nil || !ok {
+		return err
+	}
+
+	if !b.verbatim {
+		raw = expand(b.lookup, raw)
+	}
+
+	switch {
+	case raw == "":
+		m.Clear(fd)
+	case fd.IsList():
+		err = bindList(m, fd, key, raw)
+	default:
+		err = bindValue(m, fd, key, raw)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b.set = append(b.set, path)
+	return nil
+}
+
+// bindNested binds a nested message and only sets it when at least one of
+// its fields was populated, so absent sections stay nil.
+func (b *binder) bindNested(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor, key, path string) error {
+	child := m.NewField(fd).Message()
+	if m.Has(fd) {
+		child = m.Get(fd).Message()
+	}
+
+	if err := b.bind(child, key, path); err != nil {
+		return err
+	}
+
+	if isPopulated(child) {
+		m.Set(fd, protoreflect.ValueOfMessage(child))
+	}
+
+	return nil
+}
+
+func bindValue(m protoreflect.Message, fd protoreflect.FieldDescriptor,
+	key, raw string) error {
+	v, err := protobuf.ParseValue(fd, raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	m.Set(fd, v)
+	return nil
+}
+
+func bindList(m protoreflect.Message, fd protoreflect.FieldDescriptor,
+	key, raw string) error {
+	list := m.NewField(fd).List()
+	for _, part := range strings.Split(raw, listSep) {
+		v, err := protobuf.ParseValue(fd, strings.TrimSpace(part))
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+
+		list.Append(v)
+	}
+
+	m.Set(fd, protoreflect.ValueOfList(list))
+	return nil
+}
+
+// isNested reports whether fd is a singular message bound field by field.
+func isNested(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.MessageKind && !fd.IsList() &&
+		!fd.IsMap() && !protobuf.IsDuration(fd)
+}
+
+// isBindable reports whether the leaf fd can be set from a variable:
+// maps cannot, nor lists of messages other than durations.
+func isBindable(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsMap() && (!fd.IsList() ||
+		fd.Kind() != protoreflect.MessageKind || protobuf.IsDuration(fd))
+}
+
+func isPopulated(m protoreflect.Message) bool {
+	populated := false
+	m.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		populated = true
+		return false
+	})
+
+	return populated
+}
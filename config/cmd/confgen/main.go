@@ -0,0 +1,114 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command confgen writes a documented .env example and a JSON Schema for
+// a config message. It is meant for go generate:
+//
+//	//go:generate go run github.com/sentinez/shared/config/cmd/confgen -env .env.example -schema config.schema.json
+//
+// The message must be linked into confgen; services with their own config
+// message call config.EnvExample and config.JSONSchema from their own
+// generator instead. Field comments are only documented when -descriptors
+// names a descriptor set built with source info, e.g.
+//
+//	buf build -o config.binpb
+//	protoc --include_source_info --descriptor_set_out=config.binpb ...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/config"
+	"github.com/sentinez/shared/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func main() {
+	message := flag.String("message", "sentinez.setting.v1.EnvConfig",
+		"full name of the config message")
+	prefix := flag.String("prefix", config.Prefix, "env var prefix")
+	envOut := flag.String("env", "", "path of the .env example to write")
+	schemaOut := flag.String("schema", "", "path of the JSON Schema to write")
+	descriptors := flag.String("descriptors", "",
+		"path of a descriptor set with source info, for field comments")
+	flag.Parse()
+
+	err := loadComments(*descriptors)
+	if err == nil {
+		err = run(*message, *prefix, *envOut, *schemaOut)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "confgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadComments registers the source info of the descriptor set at path,
+// if any.
+func loadComments(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	return protobuf.RegisterSourceInfo(set)
+}
+
+func run(message, prefix, envOut, schemaOut string) error {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(
+		protoreflect.FullName(message))
+	if err != nil {
+		return err
+	}
+
+	msg := mt.New().Interface()
+	if envOut != "" {
+		var buf bytes.Buffer
+		if err := config.EnvExample(&buf, msg, prefix); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(envOut, buf.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+
+	if schemaOut == "" {
+		return nil
+	}
+
+	schema, err := config.JSONSchema(msg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(schemaOut, append(schema, '\n'), 0o644)
+}
@@ -15,8 +15,7 @@
 package config
 
 import (
-	"os"
-	"sync"
+	"sync/atomic"
 
 	"github.com/joho/godotenv"
 
@@ -24,21 +23,59 @@ import (
 	"github.com/sentinez/shared/zlog"
 )
 
-var envConf *settingpb.EnvConfig
-var once sync.Once
+// Loader loads and holds a service configuration. The package-level
+// functions act on a default Loader; tests can use their own Loader to
+// stay isolated from each other.
+type Loader struct {
+	env   atomic.Pointer[settingpb.EnvConfig]
+	trace atomic.Pointer[trace]
+}
+
+var std = NewLoader()
+
+// NewLoader returns an empty Loader.
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// Default returns the Loader used by the package-level functions.
+func Default() *Loader {
+	return std
+}
 
 func Env() *settingpb.EnvConfig {
-	return envConf
+	return std.Env()
 }
 
 func SetEnv(env *settingpb.EnvConfig) {
-	once.Do(func() {
-		envConf = env
-	})
+	std.SetEnv(env)
 }
 
 // LoadEnv returns the environment.
 func LoadEnv(envFile string) *settingpb.EnvConfig {
+	return std.LoadEnv(envFile)
+}
+
+// Env returns the current configuration, nil before any is set.
+func (l *Loader) Env() *settingpb.EnvConfig {
+	return l.env.Load()
+}
+
+// SetEnv sets the configuration unless one is already set.
+func (l *Loader) SetEnv(env *settingpb.EnvConfig) {
+	l.env.CompareAndSwap(nil, env)
+}
+
+// Replace sets the configuration unconditionally and returns the
+// previous one, which may be nil.
+func (l *Loader) Replace(env *settingpb.EnvConfig) *settingpb.EnvConfig {
+	l.trace.Store(nil)
+	return l.env.Swap(env)
+}
+
+// LoadEnv binds the environment, after loading envFile into it, unless a
+// configuration is already set, and returns the configuration.
+func (l *Loader) LoadEnv(envFile string) *settingpb.EnvConfig {
 	if envFile != "" {
 		err := godotenv.Load(envFile)
 		if err != nil {
@@ -46,20 +83,19 @@ func LoadEnv(envFile string) *settingpb.EnvConfig {
 		}
 	}
 
-	once.Do(func() {
-		envConf = &settingpb.EnvConfig{
-			TimescaleUri:   os.Getenv("SENZ_TIMESCALE_URI"),
-			PostgresUri:    os.Getenv("SENZ_POSTGRES_URI"),
-			ClickhouseUri:  os.Getenv("SENZ_CLICKHOUSE_URI"),
-			ConsulUri:      os.Getenv("SENZ_CONSUL_URI"),
-			SecretKey:      os.Getenv("SENZ_SECRET_KEY"),
-			GatewayAddress: os.Getenv("SENZ_GATEWAY_ADDRESS"),
-			Hostname:       os.Getenv("SENZ_HOSTNAME"),
-			HttpAddress:    os.Getenv("SENZ_HTTP_ADDRESS"),
-			ClientOrigin:   os.Getenv("SENZ_CLIENT_ORIGIN"),
-			GrpcAddress:    os.Getenv("SENZ_GRPC_ADDRESS"),
-		}
-	})
+	if env := l.env.Load(); env != nil {
+		return env
+	}
+
+	conf := &settingpb.EnvConfig{}
+	origins, err := load(conf, Environment(Prefix))
+	if err != nil {
+		zlog.Fatalf("error binding environment: err=%v", err)
+	}
+
+	if l.env.CompareAndSwap(nil, conf) {
+		l.trace.Store(&origins)
+	}
 
-	return envConf
+	return l.env.Load()
 }
@@ -0,0 +1,48 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configtest provides helpers for tests that depend on the
+// service configuration.
+package configtest
+
+import (
+	"testing"
+
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/config"
+	"google.golang.org/protobuf/proto"
+)
+
+// Override replaces the configuration returned by config.Env for the
+// duration of t. apply receives a copy of the current configuration, or
+// an empty one, and the previous configuration is restored when t ends.
+// Tests using Override must not run in parallel with each other.
+func Override(t testing.TB,
+	apply func(env *settingpb.EnvConfig)) *settingpb.EnvConfig {
+	t.Helper()
+
+	l := config.Default()
+	prev := l.Env()
+
+	next := &settingpb.EnvConfig{}
+	if prev != nil {
+		next = proto.Clone(prev).(*settingpb.EnvConfig)
+	}
+
+	apply(next)
+	l.Replace(next)
+	t.Cleanup(func() { l.Replace(prev) })
+
+	return next
+}
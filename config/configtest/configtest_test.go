@@ -0,0 +1,39 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtest
+
+import (
+	"testing"
+
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverride(t *testing.T) {
+	config.SetEnv(&settingpb.EnvConfig{Hostname: "base"})
+
+	t.Run("Override", func(t *testing.T) {
+		Override(t, func(env *settingpb.EnvConfig) {
+			env.GrpcAddress = ":9999"
+		})
+
+		assert.Equal(t, "base", config.Env().GetHostname())
+		assert.Equal(t, ":9999", config.Env().GetGrpcAddress())
+	})
+
+	assert.Equal(t, "base", config.Env().GetHostname())
+	assert.Empty(t, config.Env().GetGrpcAddress())
+}
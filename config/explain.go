@@ -0,0 +1,207 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sentinez/shared/flagx"
+	"github.com/sentinez/shared/jsonx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Explain output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// unset is the source of fields no layer provided.
+const unset = "unset"
+
+// Origin is a field of the effective configuration and the source layer
+// its value came from.
+type Origin struct {
+	Field  string `json:"field"`
+	Env    string `json:"env"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Explanation lists every field of a configuration in declaration order.
+type Explanation []Origin
+
+// Explain describes the configuration returned by Env, with secrets
+// masked, and where each value came from.
+func Explain() Explanation {
+	return std.Explain()
+}
+
+// Explain describes the configuration of l. Sources are only known for
+// configurations loaded through LoadEnv, LoadEnvConfig or WatchEnv.
+func (l *Loader) Explain() Explanation {
+	env := l.Env()
+	if env == nil {
+		return nil
+	}
+
+	return explain(env, l.trace.Load(), Prefix)
+}
+
+// PrintConfig writes Explain to w in the format requested by the flagx
+// --print-config flag. It reports whether the flag was set, so the
+// caller can exit after printing.
+func PrintConfig(w io.Writer) (bool, error) {
+	format := flagx.PrintConfig()
+	if format == "" {
+		return false, nil
+	}
+
+	return true, Explain().Write(w, format)
+}
+
+// Write renders the explanation to w as text or JSON.
+func (e Explanation) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		_, err := io.WriteString(w, e.Text())
+		return err
+	case FormatJSON:
+		data, err := jsonx.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Text renders the explanation as a table.
+func (e Explanation) Text() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FIELD\tENV\tVALUE\tSOURCE")
+	for _, o := range e {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%q\t%s\n",
+			o.Field, o.Env, o.Value, o.Source)
+	}
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+func explain(msg proto.Message, origins *trace,
+	prefix string) Explanation {
+	var t trace
+	if origins != nil {
+		t = *origins
+	}
+
+	var out Explanation
+	walkLeaves(msg.ProtoReflect(), prefix, func(l leaf) {
+		source, ok := t[l.path]
+		if !ok {
+			source = unset
+		}
+
+		out = append(out, Origin{
+			Field:  l.path,
+			Env:    l.env,
+			Value:  explainValue(l.msg, l.fd),
+			Source: source,
+		})
+	})
+
+	return out
+}
+
+// leaf is a field that is not a nested message, reached by walkLeaves.
+type leaf struct {
+	msg  protoreflect.Message
+	fd   protoreflect.FieldDescriptor
+	path string
+	env  string
+}
+
+// walkLeaves calls visit for every leaf field of m in declaration order,
+// descending into nested messages and skipping recursive ones.
+func walkLeaves(m protoreflect.Message, prefix string, visit func(leaf)) {
+	w := &leafWalker{visit: visit}
+	w.walk(m, "", prefix)
+}
+
+type leafWalker struct {
+	visit func(leaf)
+	path  []protoreflect.FullName
+}
+
+func (w *leafWalker) walk(m protoreflect.Message, prefix, env string) {
+	name := m.Descriptor().FullName()
+	if slices.Contains(w.path, name) {
+		return
+	}
+
+	w.path = append(w.path, name)
+	defer func() { w.path = w.path[:len(w.path)-1] }()
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path, key := fieldPath(prefix, fd), EnvName(env, fd)
+		if isNested(fd) {
+			w.walk(m.Get(fd).Message(), path, key)
+			continue
+		}
+
+		w.visit(leaf{msg: m, fd: fd, path: path, env: key})
+	}
+}
+
+func explainValue(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return ""
+	}
+
+	v := m.Get(fd)
+	switch {
+	case fd.IsList():
+		parts := make([]string, 0, v.List().Len())
+		for i := 0; i < v.List().Len(); i++ {
+			parts = append(parts, Mask(fd, formatValue(fd, v.List().Get(i))))
+		}
+		return strings.Join(parts, listSep)
+	case fd.IsMap():
+		var parts []string
+		v.Map().Range(func(k protoreflect.MapKey,
+			mv protoreflect.Value) bool {
+			val := Mask(fd, formatValue(fd.MapValue(), mv))
+			parts = append(parts, k.String()+"="+val)
+			return true
+		})
+		slices.Sort(parts)
+		return strings.Join(parts, listSep)
+	}
+
+	return Mask(fd, formatValue(fd, v))
+}
@@ -0,0 +1,396 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	validatepb "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/sentinez/shared/jsonx"
+	"github.com/sentinez/shared/protobuf"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect JSONSchema emits.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches durations in their protojson form, e.g. 90s or
+// 1.5s, which is how config files spell them.
+const durationPattern = `^-?[0-9]+(\.[0-9]{1,9})?s$`
+
+// schemaKeywords maps protovalidate rule names to JSON Schema keywords.
+var schemaKeywords = map[protoreflect.Name]string{
+	"const":     "const",
+	"min_len":   "minLength",
+	"max_len":   "maxLength",
+	"pattern":   "pattern",
+	"in":        "enum",
+	"gte":       "minimum",
+	"gt":        "exclusiveMinimum",
+	"lte":       "maximum",
+	"lt":        "exclusiveMaximum",
+	"min_items": "minItems",
+	"max_items": "maxItems",
+}
+
+// EnvExample writes a documented .env example for msg. Every variable is
+// preceded by its field comment, type and protovalidate rules, and set to
+// its value in msg, so msg doubles as the defaults. Secrets are always
+// left empty, and maps and lists of messages, which Bind cannot set, are
+// left out. Comments are only available when the descriptor was built
+// with source info or its file was registered with
+// protobuf.RegisterSourceInfo.
+func EnvExample(w io.Writer, msg proto.Message, prefix string) error {
+	var sb strings.Builder
+	walkLeaves(msg.ProtoReflect(), prefix, func(l leaf) {
+		if !isBindable(l.fd) {
+			return
+		}
+
+		for _, line := range protobuf.Comments(l.fd) {
+			fmt.Fprintf(&sb, "# %s\n", line)
+		}
+
+		fmt.Fprintf(&sb, "# type: %s\n", typeName(l.fd))
+		if rules := ruleList(l.fd); len(rules) > 0 {
+			fmt.Fprintf(&sb, "# rules: %s\n", strings.Join(rules, ", "))
+		}
+
+		value := ""
+		if !IsSecret(l.fd) {
+			value = explainValue(l.msg, l.fd)
+		}
+
+		fmt.Fprintf(&sb, "%s=%s\n\n", l.env, value)
+	})
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// JSONSchema returns a JSON Schema of msg as a YAML or JSON config file,
+// with field comments, types, the values set in msg as defaults and the
+// protovalidate rules JSON Schema can express. Fields are listed under
+// both their proto and JSON names, e.g. http_address and httpAddress, as
+// File accepts either. Comments are available as in EnvExample.
+func JSONSchema(msg proto.Message) ([]byte, error) {
+	s := &schemaBuilder{}
+	schema := s.message(msg.ProtoReflect())
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = string(msg.ProtoReflect().Descriptor().FullName())
+
+	return jsonx.MarshalIndent(schema, "", "  ")
+}
+
+type schemaBuilder struct {
+	path []protoreflect.FullName
+}
+
+func (s *schemaBuilder) message(m protoreflect.Message) map[string]any {
+	name := m.Descriptor().FullName()
+	if slices.Contains(s.path, name) {
+		return map[string]any{"type": "object"}
+	}
+
+	s.path = append(s.path, name)
+	defer func() { s.path = s.path[:len(s.path)-1] }()
+
+	props := map[string]any{}
+	var required []protoreflect.FieldDescriptor
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[string(fd.Name())] = s.field(m, fd)
+		props[fd.JSONName()] = props[string(fd.Name())]
+		if fieldRules(fd).GetRequired() {
+			required = append(required, fd)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	addRequired(schema, required)
+
+	return schema
+}
+
+// addRequired requires the fields on schema, by either name when their
+// proto and JSON names differ.
+func addRequired(schema map[string]any,
+	fields []protoreflect.FieldDescriptor) {
+	var required []string
+	var either []any
+	for _, fd := range fields {
+		name, jsonName := string(fd.Name()), fd.JSONName()
+		if name == jsonName {
+			required = append(required, name)
+			continue
+		}
+
+		either = append(either, map[string]any{"anyOf": []any{
+			map[string]any{"required": []string{name}},
+			map[string]any{"required": []string{jsonName}},
+		}})
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(either) > 0 {
+		schema["allOf"] = either
+	}
+}
+
+func (s *schemaBuilder) field(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor) map[string]any {
+	var schema map[string]any
+	switch {
+	case fd.IsMap():
+		schema = map[string]any{
+			"type":                 "object",
+			"additionalProperties": s.value(m, fd.MapValue()),
+		}
+	case fd.IsList():
+		schema = map[string]any{"type": "array", "items": s.value(m, fd)}
+	default:
+		schema = s.value(m, fd)
+	}
+
+	if line := strings.Join(protobuf.Comments(fd), " "); line != "" {
+		schema["description"] = line
+	}
+
+	if v, ok := defaultValue(m, fd); ok {
+		schema["default"] = v
+	}
+
+	addRules(schema, fd)
+	return schema
+}
+
+// value returns the schema of a single value of fd.
+func (s *schemaBuilder) value(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+	case protoreflect.EnumKind:
+		return map[string]any{"enum": enumNames(fd.Enum())}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if protobuf.IsDuration(fd) {
+			return map[string]any{"type": "string", "pattern": durationPattern}
+		}
+
+		if fd.IsList() || fd.ContainingMessage().IsMapEntry() {
+			return s.message(dynamicpb.NewMessage(fd.Message()))
+		}
+
+		return s.message(m.Get(fd).Message())
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return map[string]any{"type": "string"}
+	}
+
+	return map[string]any{"type": "integer"}
+}
+
+// addRules maps the rules of fd to JSON Schema keywords on schema, or on
+// its items for repeated fields.
+func addRules(schema map[string]any, fd protoreflect.FieldDescriptor) {
+	_, kind := kindRules(fieldRules(fd))
+	if kind == nil {
+		return
+	}
+
+	target := schema
+	if items, ok := schema["items"].(map[string]any); ok &&
+		kind.Descriptor().Name() != "RepeatedRules" {
+		target = items
+	}
+
+	kind.Range(func(rd protoreflect.FieldDescriptor,
+		v protoreflect.Value) bool {
+		// Duration bounds have no JSON Schema keyword for strings.
+		keyword, ok := schemaKeywords[rd.Name()]
+		if !ok || (keyword == "enum" && fd.Enum() != nil) ||
+			rd.Kind() == protoreflect.MessageKind {
+			return true
+		}
+
+		if val, ok := ruleValue(rd, v); ok {
+			target[keyword] = val
+		}
+		return true
+	})
+}
+
+// ruleList renders the rules of fd as name=value pairs, sorted.
+func ruleList(fd protoreflect.FieldDescriptor) []string {
+	rules := fieldRules(fd)
+	var out []string
+	if rules.GetRequired() {
+		out = append(out, "required")
+	}
+
+	prefix, kind := kindRules(rules)
+	if kind == nil {
+		return out
+	}
+
+	kind.Range(func(rd protoreflect.FieldDescriptor,
+		v protoreflect.Value) bool {
+		if val, ok := ruleValue(rd, v); ok {
+			out = append(out, fmt.Sprintf("%s.%s=%v",
+				prefix, rd.Name(), val))
+		}
+		return true
+	})
+
+	slices.Sort(out)
+	return out
+}
+
+func fieldRules(fd protoreflect.FieldDescriptor) *validatepb.FieldRules {
+	if fd.Options() == nil {
+		return nil
+	}
+
+	rules, _ := proto.GetExtension(fd.Options(),
+		validatepb.E_Field).(*validatepb.FieldRules)
+	return rules
+}
+
+// kindRules returns the type-specific rules, e.g. StringRules, and the
+// name they are set under, e.g. string.
+func kindRules(rules *validatepb.FieldRules) (protoreflect.Name,
+	protoreflect.Message) {
+	if rules == nil {
+		return "", nil
+	}
+
+	m := rules.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("type"))
+	if fd == nil {
+		return "", nil
+	}
+
+	return fd.Name(), m.Get(fd).Message()
+}
+
+// ruleValue converts a rule value to plain Go values. Rules holding other
+// messages than durations are skipped.
+func ruleValue(rd protoreflect.FieldDescriptor,
+	v protoreflect.Value) (any, bool) {
+	if !rd.IsList() {
+		return ruleScalar(rd, v)
+	}
+
+	items := make([]any, 0, v.List().Len())
+	for i := 0; i < v.List().Len(); i++ {
+		item, ok := ruleScalar(rd, v.List().Get(i))
+		if !ok {
+			return nil, false
+		}
+		items = append(items, item)
+	}
+
+	return items, true
+}
+
+func ruleScalar(rd protoreflect.FieldDescriptor,
+	v protoreflect.Value) (any, bool) {
+	if rd.Kind() != protoreflect.MessageKind {
+		return v.Interface(), true
+	}
+
+	if d, ok := v.Message().Interface().(*durationpb.Duration); ok {
+		return d.AsDuration().String(), true
+	}
+
+	return nil, false
+}
+
+// defaultValue returns the value of a singular scalar field as a JSON
+// value, secrets excluded.
+func defaultValue(m protoreflect.Message,
+	fd protoreflect.FieldDescriptor) (any, bool) {
+	if !m.Has(fd) || fd.IsList() || fd.IsMap() || IsSecret(fd) {
+		return nil, false
+	}
+
+	switch {
+	case protobuf.IsDuration(fd):
+		return durationJSON(m.Get(fd).Message())
+	case fd.Kind() == protoreflect.EnumKind:
+		return explainValue(m, fd), true
+	case fd.Kind() == protoreflect.MessageKind,
+		fd.Kind() == protoreflect.BytesKind:
+		return nil, false
+	}
+
+	return m.Get(fd).Interface(), true
+}
+
+// durationJSON returns the protojson form of the duration m.
+func durationJSON(m protoreflect.Message) (any, bool) {
+	data, err := protojson.Marshal(m.Interface())
+	if err != nil {
+		return nil, false
+	}
+
+	var v string
+	if err := jsonx.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+func typeName(fd protoreflect.FieldDescriptor) string {
+	name := fd.Kind().String()
+	switch {
+	case fd.Kind() == protoreflect.EnumKind:
+		name = "enum (" + strings.Join(enumNames(fd.Enum()), "|") + ")"
+	case protobuf.IsDuration(fd):
+		name = "duration"
+	}
+
+	if fd.IsList() {
+		return "comma-separated list of " + name
+	}
+
+	return name
+}
+
+func enumNames(ed protoreflect.EnumDescriptor) []string {
+	names := make([]string, 0, ed.Values().Len())
+	for i := 0; i < ed.Values().Len(); i++ {
+		names = append(names, string(ed.Values().Get(i).Name()))
+	}
+
+	return names
+}
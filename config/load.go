@@ -0,0 +1,144 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/flagx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Load merges sources into msg in order, later sources taking precedence.
+// A field is only overridden when a source sets it, possibly to its zero
+// value; repeated fields are replaced rather than appended and nested
+// messages are merged field by field.
+func Load(msg proto.Message, sources ...Source) error {
+	_, err := load(msg, sources...)
+	return err
+}
+
+// trace maps the dotted path of each loaded field to the name of the
+// source that set it last.
+type trace map[string]string
+
+func load(msg proto.Message, sources ...Source) (trace, error) {
+	origins := trace{}
+	dst := msg.ProtoReflect()
+	for _, src := range sources {
+		layer := dst.New()
+		paths, err := src.Load(layer.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("error loading config from %s: %w",
+				src.Name(), err)
+		}
+
+		for _, path := range paths {
+			overlay(dst, layer, path)
+			origins[path] = src.Name()
+		}
+	}
+
+	return origins, nil
+}
+
+// Layers returns the standard layer order: defaults, then the config
+// file, then the .env file, then the process environment, then flags.
+// Empty file and envFile skip their layer.
+func Layers(defaults proto.Message, file, envFile string) []Source {
+	return Profile{}.Layers(defaults, file, envFile)
+}
+
+// LoadEnvConfig loads the service configuration through the standard
+// layers and the profile of the flagx run mode, then validates it, so a
+// misconfigured service fails before starting any server. Unlike
+// LoadEnv, it reports failures instead of exiting. The result replaces
+// the configuration returned by Env.
+func LoadEnvConfig(file, envFile string) (*settingpb.EnvConfig, error) {
+	return std.LoadEnvConfig(file, envFile)
+}
+
+// LoadEnvConfig loads the configuration as the package-level
+// LoadEnvConfig does and makes it the configuration of l.
+func (l *Loader) LoadEnvConfig(file,
+	envFile string) (*settingpb.EnvConfig, error) {
+	profile := ProfileFor(flagx.Get().GetEnvMode())
+
+	conf := &settingpb.EnvConfig{}
+	origins, err := load(conf, profile.Layers(nil, file, envFile)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := profile.Validate(conf, Prefix); err != nil {
+		return nil, err
+	}
+
+	l.Replace(conf)
+	l.trace.Store(&origins)
+	return conf, nil
+}
+
+// overlay copies the field at the dotted path from src into dst, clearing
+// it in dst when src holds its zero value.
+func overlay(dst, src protoreflect.Message, path string) {
+	names := strings.Split(path, ".")
+	last := len(names) - 1
+	for _, name := range names[:last] {
+		fd := src.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if !src.Has(fd) && !dst.Has(fd) {
+			return
+		}
+
+		dst, src = dst.Mutable(fd).Message(), src.Get(fd).Message()
+	}
+
+	fd := src.Descriptor().Fields().ByName(protoreflect.Name(names[last]))
+	if src.Has(fd) {
+		dst.Set(fd, src.Get(fd))
+	} else {
+		dst.Clear(fd)
+	}
+}
+
+// populated returns the paths of the populated leaf fields of m.
+func populated(m protoreflect.Message, prefix string) []string {
+	var paths []string
+	m.Range(func(fd protoreflect.FieldDescriptor,
+		v protoreflect.Value) bool {
+		path := fieldPath(prefix, fd)
+		if isNested(fd) {
+			paths = append(paths, populated(v.Message(), path)...)
+		} else {
+			paths = append(paths, path)
+		}
+
+		return true
+	})
+
+	return paths
+}
+
+// fieldPath appends the name of fd to the dotted path prefix.
+func fieldPath(prefix string, fd protoreflect.FieldDescriptor) string {
+	if prefix == "" {
+		return string(fd.Name())
+	}
+
+	return prefix + "." + string(fd.Name())
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// masked replaces the value of a secret field.
+const masked = "******"
+
+// secretNames are field name fragments treated as secrets even without
+// the debug_redact option.
+var secretNames = []string{"secret", "password", "token", "credential"}
+
+// IsSecret reports whether fd holds a secret, either through the
+// debug_redact field option or by its name.
+func IsSecret(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if ok && opts.GetDebugRedact() {
+		return true
+	}
+
+	name := strings.ToLower(string(fd.Name()))
+	for _, s := range secretNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Mask hides raw when fd is a secret and strips the password of URIs.
+// Empty values are kept so missing settings remain visible.
+func Mask(fd protoreflect.FieldDescriptor, raw string) string {
+	if raw == "" {
+		return raw
+	}
+
+	if IsSecret(fd) {
+		return masked
+	}
+
+	return MaskURI(raw)
+}
+
+// MaskURI replaces the password of a URI as url.URL.Redacted does.
+// URIs url.Parse rejects, e.g. with multiple hosts or an unescaped % or #
+// in the password, are split as dsn does instead. Values with an @ that
+// cannot be told apart from the password are masked as a whole, and the
+// others, without a password, are returned as is.
+func MaskURI(raw string) string {
+	u, err := url.Parse(raw)
+	if err == nil && u.User != nil {
+		return u.Redacted()
+	}
+
+	if !strings.Contains(raw, "@") {
+		return raw
+	}
+
+	return maskUserinfo(raw)
+}
+
+// maskUserinfo masks the password of the userinfo of raw, the part of
+// its authority before the last @.
+func maskUserinfo(raw string) string {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return masked
+	}
+
+	authority := rest
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		authority = rest[:i]
+	}
+
+	at := strings.LastIndex(authority, "@")
+	if at < 0 {
+		return masked
+	}
+
+	user, _, ok := strings.Cut(authority[:at], ":")
+	if !ok {
+		return raw
+	}
+
+	return scheme + "://" + user + ":xxxxx" + rest[at:]
+}
+
+// Redact returns a copy of msg with secrets masked and URI passwords
+// stripped, in nested messages, lists and maps alike. Use it whenever a
+// config is printed, logged or dumped.
+func Redact[T proto.Message](msg T) T {
+	out := proto.Clone(msg).(T)
+	redact(out.ProtoReflect())
+	return out
+}
+
+// Format renders msg as JSON after redacting it.
+func Format(msg proto.Message) string {
+	return protojson.Format(Redact(msg))
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor,
+		v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, redactValue(fd, list.Get(i)))
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey,
+				mv protoreflect.Value) bool {
+				v.Map().Set(k, redactEntry(fd, mv))
+				return true
+			})
+		default:
+			m.Set(fd, redactValue(fd, v))
+		}
+		return true
+	})
+}
+
+func redactValue(fd protoreflect.FieldDescriptor,
+	v protoreflect.Value) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(Mask(fd, v.String()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		redact(v.Message())
+	}
+
+	return v
+}
+
+// redactEntry masks string map values by the secrecy of the map field.
+func redactEntry(fd protoreflect.FieldDescriptor,
+	v protoreflect.Value) protoreflect.Value {
+	if fd.MapValue().Kind() == protoreflect.StringKind {
+		return protoreflect.ValueOfString(Mask(fd, v.String()))
+	}
+
+	return redactValue(fd.MapValue(), v)
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Run modes, as carried by flagx.
+const (
+	ModeDev     = "dev"
+	ModeProd    = "prod"
+	ModeSandbox = "sandbox"
+)
+
+// Profile is the configuration overlay of a run mode.
+type Profile struct {
+	// Mode selects the overlay files, e.g. config.prod.yaml and .env.prod.
+	Mode string
+
+	// Required lists the dotted field paths that must be set in Mode.
+	Required []string
+}
+
+// ProfileFor returns the built-in profile of the service configuration
+// for mode. Production requires the secret key and the client origin.
+func ProfileFor(mode string) Profile {
+	p := Profile{Mode: mode}
+	if mode == ModeProd {
+		p.Required = []string{"secret_key", "client_origin"}
+	}
+
+	return p
+}
+
+// Layers returns the standard layers with the profile overlays applied
+// on top of their base file: defaults, file, profile file, .env file,
+// profile .env file, process environment and flags. Missing profile
+// files are skipped.
+func (p Profile) Layers(defaults proto.Message,
+	file, envFile string) []Source {
+	sources := []Source{Defaults(defaults)}
+	if file != "" {
+		sources = append(sources, File(file))
+		if p.Mode != "" {
+			sources = append(sources, Optional(File(profileFile(file,
+				p.Mode))))
+		}
+	}
+
+	if envFile != "" {
+		sources = append(sources, Dotenv(envFile, Prefix))
+		if p.Mode != "" {
+			sources = append(sources, Optional(Dotenv(envFile+"."+p.Mode,
+				Prefix)))
+		}
+	}
+
+	return append(sources, Environment(Prefix), Flags(nil))
+}
+
+// Validate runs the protovalidate rules of msg and checks the fields the
+// profile requires, reporting all violations together.
+func (p Profile) Validate(msg proto.Message, prefix string) error {
+	violations, err := validate(msg, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range p.Required {
+		if !isSet(msg.ProtoReflect(), path) {
+			violations = append(violations, Violation{
+				Field:   path,
+				Env:     joinEnv(prefix, strings.ReplaceAll(path, ".", "_")),
+				Rule:    "required",
+				Message: fmt.Sprintf("value is required in %s mode", p.Mode),
+			})
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+// Optional wraps a source so that a missing file is not an error.
+func Optional(src Source) Source {
+	return &optionalSource{Source: src}
+}
+
+type optionalSource struct {
+	Source
+}
+
+func (s *optionalSource) Load(msg proto.Message) ([]string, error) {
+	paths, err := s.Source.Load(msg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	return paths, err
+}
+
+// profileFile inserts mode before the extension, config.yaml becoming
+// config.prod.yaml.
+func profileFile(file, mode string) string {
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + "." + mode + ext
+}
+
+// isSet reports whether the field at the dotted path is populated.
+func isSet(m protoreflect.Message, path string) bool {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || !m.Has(fd) {
+			return false
+		}
+
+		if i < len(names)-1 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() {
+				return false
+			}
+
+			m = m.Get(fd).Message()
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FileSuffix marks a variable whose value is the path of a file holding
+// the actual value, as mounted by Docker or Kubernetes secrets.
+const FileSuffix = "_FILE"
+
+// reference matches ${VAR} references inside values.
+var reference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolve returns the value of key from lookup, falling back to the file
+// named by key_FILE. A key set to the empty string without a file is
+// reported as set.
+func resolve(lookup lookupFunc, key string) (string, bool, error) {
+	raw, ok := lookup(key)
+	if !ok || raw == "" {
+		path, isFile := lookup(key + FileSuffix)
+		if !isFile || path == "" {
+			return "", ok, nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("error reading %s%s: %w",
+				key, FileSuffix, err)
+		}
+
+		raw = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return raw, true, nil
+}
+
+// expand replaces ${VAR} references, looking VAR up in lookup first and
+// in the process environment second. Unset variables expand to empty.
+// A bare $VAR is left alone so passwords containing $ survive.
+func expand(lookup lookupFunc, raw string) string {
+	return reference.ReplaceAllStringFunc(raw, func(ref string) string {
+		name := reference.FindStringSubmatch(ref)[1]
+		if v, ok := lookup(name); ok {
+			return v
+		}
+
+		return os.Getenv(name)
+	})
+}
+
+// expandMessage expands ${VAR} references in the string values of m and
+// of its nested messages, lists and maps, as expand does.
+func expandMessage(m protoreflect.Message, lookup lookupFunc) {
+	m.Range(func(fd protoreflect.FieldDescriptor,
+		v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, expandValue(fd, list.Get(i), lookup))
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey,
+				mv protoreflect.Value) bool {
+				v.Map().Set(k, expandValue(fd.MapValue(), mv, lookup))
+				return true
+			})
+		default:
+			m.Set(fd, expandValue(fd, v, lookup))
+		}
+
+		return true
+	})
+}
+
+func expandValue(fd protoreflect.FieldDescriptor, v protoreflect.Value,
+	lookup lookupFunc) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(expand(lookup, v.String()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		expandMessage(v.Message(), lookup)
+	}
+
+	return v
+}
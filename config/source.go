@@ -0,0 +1,235 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/sentinez/shared/jsonx"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is a single configuration layer.
+type Source interface {
+	// Name identifies the layer in errors.
+	Name() string
+
+	// Load fills msg with the values provided by the layer and returns
+	// the dotted paths of the fields it set, including the ones set to
+	// their zero value, which then override earlier layers.
+	Load(msg proto.Message) ([]string, error)
+}
+
+// Defaults returns a source that provides the fields set in msg.
+func Defaults(msg proto.Message) Source {
+	return &defaultSource{msg: msg}
+}
+
+// File returns a source that reads a YAML or JSON file, chosen by its
+// extension. Keys are proto field names or their JSON names. ${VAR}
+// references in string values are expanded from the process environment
+// once the file is decoded, so the variables cannot alter its structure.
+func File(path string) Source {
+	return &fileSource{path: path}
+}
+
+// Dotenv returns a source that reads a .env file without modifying the
+// process environment. Keys are named as in Bind. Values are expanded by
+// the .env parser only.
+func Dotenv(path, prefix string) Source {
+	return &dotenvSource{path: path, prefix: prefix}
+}
+
+// Environment returns a source that reads the process environment as in Bind.
+func Environment(prefix string) Source {
+	return &envSource{prefix: prefix}
+}
+
+// Flags returns a source that reads the flags explicitly set on fs.
+// A field maps to the lower-cased Bind name without prefix, e.g.
+// grpc_address reads --grpc_address. A nil fs uses pflag.CommandLine,
+// where flagx registers its flags.
+func Flags(fs *pflag.FlagSet) Source {
+	if fs == nil {
+		fs = pflag.CommandLine
+	}
+
+	return &flagSource{fs: fs}
+}
+
+type defaultSource struct {
+	msg proto.Message
+}
+
+func (s *defaultSource) Name() string { return "defaults" }
+
+func (s *defaultSource) Load(msg proto.Message) ([]string, error) {
+	if s.msg == nil {
+		return nil, nil
+	}
+
+	proto.Merge(msg, s.msg)
+	return populated(msg.ProtoReflect(), ""), nil
+}
+
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Name() string { return s.path }
+
+func (s *fileSource) Load(msg proto.Message) ([]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q",
+			filepath.Ext(s.path))
+	}
+
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	expandMessage(msg.ProtoReflect(), os.LookupEnv)
+
+	var doc map[string]any
+	if err := jsonx.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return jsonPaths(msg.ProtoReflect().Descriptor(), doc, ""), nil
+}
+
+// jsonPaths returns the dotted paths of the fields of md present in doc,
+// keyed by proto or JSON names, descending into nested messages.
+func jsonPaths(md protoreflect.MessageDescriptor, doc map[string]any,
+	prefix string) []string {
+	var paths []string
+	for key, v := range doc {
+		fd := md.Fields().ByName(protoreflect.Name(key))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(key)
+		}
+
+		if fd == nil {
+			continue
+		}
+
+		path := fieldPath(prefix, fd)
+		obj, ok := v.(map[string]any)
+		if ok && isNested(fd) && !isWellKnown(fd.Message()) {
+			paths = append(paths, jsonPaths(fd.Message(), obj, path)...)
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc == nil {
+		return []byte("{}"), nil
+	}
+
+	return jsonx.Marshal(doc)
+}
+
+// isWellKnown reports whether md is a google.protobuf type, which JSON
+// sets as a whole.
+func isWellKnown(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile().Package() == "google.protobuf"
+}
+
+type dotenvSource struct {
+	path   string
+	prefix string
+}
+
+func (s *dotenvSource) Name() string { return s.path }
+
+func (s *dotenvSource) Load(msg proto.Message) ([]string, error) {
+	vars, err := godotenv.Read(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &binder{verbatim: true, lookup: func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}}
+	err = b.bind(msg.ProtoReflect(), s.prefix, "")
+	return b.set, err
+}
+
+type envSource struct {
+	prefix string
+}
+
+func (s *envSource) Name() string { return "env" }
+
+func (s *envSource) Load(msg proto.Message) ([]string, error) {
+	b := &binder{lookup: os.LookupEnv}
+	err := b.bind(msg.ProtoReflect(), s.prefix, "")
+	return b.set, err
+}
+
+type flagSource struct {
+	fs *pflag.FlagSet
+}
+
+func (s *flagSource) Name() string { return "flags" }
+
+func (s *flagSource) Load(msg proto.Message) ([]string, error) {
+	b := &binder{lookup: s.lookup}
+	err := b.bind(msg.ProtoReflect(), "", "")
+	return b.set, err
+}
+
+func (s *flagSource) lookup(key string) (string, bool) {
+	f := s.fs.Lookup(strings.ToLower(key))
+	if f == nil || !f.Changed {
+		return "", false
+	}
+
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Join(sv.GetSlice(), listSep), true
+	}
+
+	return f.Value.String(), true
+}
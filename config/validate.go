@@ -0,0 +1,156 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/sentinez/shared/protobuf"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Violation is a single failed validation rule of a config field.
+type Violation struct {
+	Field   string
+	Env     string
+	Rule    string
+	Value   string
+	Message string
+}
+
+// ValidationError reports every violation of a config message at once.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error renders the violations as a table.
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid configuration: %d violation(s)\n",
+		len(e.Violations))
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FIELD\tENV\tRULE\tVALUE\tMESSAGE")
+	for _, v := range e.Violations {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%q\t%s\n",
+			v.Field, v.Env, v.Rule, v.Value, v.Message)
+	}
+	_ = w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Validate runs the protovalidate rules of msg. Rule violations are
+// returned together as a *ValidationError whose env names use prefix and
+// whose values are masked.
+func Validate(msg proto.Message, prefix string) error {
+	violations, err := validate(msg, prefix)
+	if err != nil || len(violations) == 0 {
+		return err
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+func validate(msg proto.Message, prefix string) ([]Violation, error) {
+	err := protobuf.Validate(msg)
+
+	var verr *protovalidate.ValidationError
+	if !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	violations := make([]Violation, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		violations = append(violations, toViolation(v, prefix))
+	}
+
+	return violations, nil
+}
+
+func toViolation(v *protovalidate.Violation, prefix string) Violation {
+	env := prefix
+	for _, el := range v.Proto.GetField().GetElements() {
+		env = joinEnv(env, el.GetFieldName())
+	}
+
+	out := Violation{
+		Field:   protovalidate.FieldPathString(v.Proto.GetField()),
+		Env:     env,
+		Rule:    v.Proto.GetRuleId(),
+		Message: v.Proto.GetMessage(),
+	}
+
+	if v.FieldDescriptor != nil && v.FieldValue.IsValid() {
+		out.Value = Mask(v.FieldDescriptor,
+			formatValue(v.FieldDescriptor, v.FieldValue))
+	}
+
+	return out
+}
+
+// formatValue renders a single value of fd, enums by name.
+func formatValue(fd protoreflect.FieldDescriptor,
+	v protoreflect.Value) string {
+	switch val := v.Interface().(type) {
+	case protoreflect.Message:
+		return formatMessage(val)
+	case protoreflect.EnumNumber:
+		if ev := fd.Enum().Values().ByNumber(val); ev != nil {
+			return string(ev.Name())
+		}
+	}
+
+	return v.String()
+}
+
+var (
+	durationName = (&durationpb.Duration{}).ProtoReflect().
+			Descriptor().FullName()
+	timestampName = (&timestamppb.Timestamp{}).ProtoReflect().
+			Descriptor().FullName()
+)
+
+// formatMessage renders durations as time.Duration does, e.g. 1m30s, and
+// timestamps in RFC 3339.
+func formatMessage(m protoreflect.Message) string {
+	switch m.Descriptor().FullName() {
+	case durationName:
+		sec, nsec := secondsNanos(m)
+		return (time.Duration(sec)*time.Second +
+			time.Duration(nsec)).String()
+	case timestampName:
+		sec, nsec := secondsNanos(m)
+		return time.Unix(sec, nsec).UTC().Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(m.Interface())
+}
+
+// secondsNanos returns the fields of a Duration or Timestamp, which may
+// be a dynamic message.
+func secondsNanos(m protoreflect.Message) (int64, int64) {
+	fields := m.Descriptor().Fields()
+	return m.Get(fields.ByName("seconds")).Int(),
+		m.Get(fields.ByName("nanos")).Int()
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/cron"
+	"github.com/sentinez/shared/flagx"
+	"github.com/sentinez/shared/zlog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Change describes a configuration reload. Fields lists the dotted paths
+// of the fields whose value changed.
+type Change[T proto.Message] struct {
+	Old    T
+	New    T
+	Fields []string
+}
+
+// Watcher reloads a configuration from its sources and atomically swaps
+// the current value when it changes. Subscribers are notified after the
+// swap. A reload that fails to load or validate keeps the previous value.
+type Watcher[T proto.Message] struct {
+	prefix  string
+	sources []Source
+	current atomic.Value
+	origins atomic.Pointer[trace]
+
+	// check replaces Validate when set.
+	check func(T) error
+
+	mu   sync.Mutex
+	subs []func(Change[T])
+}
+
+// NewWatcher creates a watcher starting from the already loaded initial
+// value. prefix names env vars in validation reports.
+func NewWatcher[T proto.Message](initial T, prefix string,
+	sources ...Source) *Watcher[T] {
+	w := &Watcher[T]{prefix: prefix, sources: sources}
+	w.current.Store(initial)
+	return w
+}
+
+// Current returns the current configuration. It must not be modified.
+func (w *Watcher[T]) Current() T {
+	return w.current.Load().(T)
+}
+
+// Subscribe registers fn to be called after every applied reload.
+// fn runs while the watcher is locked and must not call back into it.
+func (w *Watcher[T]) Subscribe(fn func(Change[T])) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subs = append(w.subs, fn)
+}
+
+// Reload loads and validates the sources and swaps the configuration if
+// it changed.
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	old := w.Current()
+	next := old.ProtoReflect().New().Interface().(T)
+	origins, err := load(next, w.sources...)
+	if err != nil {
+		return err
+	}
+
+	if err := w.validate(next); err != nil {
+		return err
+	}
+
+	w.origins.Store(&origins)
+	fields := diff(old.ProtoReflect(), next.ProtoReflect(), "")
+	if len(fields) == 0 {
+		return nil
+	}
+
+	w.current.Store(next)
+	change := Change[T]{Old: old, New: next, Fields: fields}
+	for _, fn := range w.subs {
+		fn(change)
+	}
+
+	return nil
+}
+
+// Explain describes the current configuration and where each value came
+// from. Sources are only known once the watcher has applied a reload.
+func (w *Watcher[T]) Explain() Explanation {
+	return explain(w.Current(), w.origins.Load(), w.prefix)
+}
+
+func (w *Watcher[T]) validate(msg T) error {
+	if w.check != nil {
+		return w.check(msg)
+	}
+
+	return Validate(msg, w.prefix)
+}
+
+// Watch polls the sources every interval until ctx is done. Rejected
+// reloads are logged and keep the previous configuration.
+func (w *Watcher[T]) Watch(ctx context.Context, interval time.Duration) {
+	cron.Start(ctx, interval, func() {
+		if err := w.Reload(); err != nil {
+			zlog.Errorf("config reload rejected: err=%v", err)
+		}
+	})
+}
+
+// WatchEnv loads the service configuration as LoadEnvConfig does and
+// keeps the value returned by Env up to date every interval.
+func WatchEnv(ctx context.Context, file, envFile string,
+	interval time.Duration) (*Watcher[*settingpb.EnvConfig], error) {
+	return std.WatchEnv(ctx, file, envFile, interval)
+}
+
+// WatchEnv loads the configuration of l as LoadEnvConfig does and keeps
+// it up to date every interval.
+func (l *Loader) WatchEnv(ctx context.Context, file, envFile string,
+	interval time.Duration) (*Watcher[*settingpb.EnvConfig], error) {
+	conf, err := l.LoadEnvConfig(file, envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := ProfileFor(flagx.Get().GetEnvMode())
+	w := NewWatcher(conf, Prefix, profile.Layers(nil, file, envFile)...)
+	w.check = func(c *settingpb.EnvConfig) error {
+		return profile.Validate(c, Prefix)
+	}
+
+	w.origins.Store(l.trace.Load())
+	w.Subscribe(func(c Change[*settingpb.EnvConfig]) {
+		l.env.Store(c.New)
+		l.trace.Store(w.origins.Load())
+	})
+
+	w.Watch(ctx, interval)
+	return w, nil
+}
+
+// diff returns the paths of the fields that differ between a and b.
+func diff(a, b protoreflect.Message, prefix string) []string {
+	var fields []string
+	fds := a.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		path := fieldPath(prefix, fd)
+
+		switch {
+		case isNested(fd) && a.Has(fd) && b.Has(fd):
+			fields = append(fields,
+				diff(a.Get(fd).Message(), b.Get(fd).Message(), path)...)
+		case !a.Get(fd).Equal(b.Get(fd)):
+			fields = append(fields, path)
+		}
+	}
+
+	return fields
+}
@@ -41,3 +41,33 @@ func Start(ctx context.Context, interval time.Duration, job func()) {
 		}
 	}()
 }
+
+// StartSchedule starts a cron job that runs according to a schedule.
+// Unlike Start, the first run only happens immediately when it is not
+// blacked out; otherwise it runs at the blackout end under PolicyDefer
+// and at the schedule's next run time under PolicySkip. Runs follow the
+// schedule rather than the wake-up times, so timer latency does not
+// accumulate. The job stops when ctx is done or the schedule has no next
+// run.
+func StartSchedule(ctx context.Context, s Schedule, job func()) {
+	now := time.Now()
+	next := s.first(now)
+	if next.Equal(now) {
+		job()
+		next = s.Next(now)
+	}
+
+	go func() {
+		for !next.IsZero() {
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-timer.C:
+				job()
+				next = s.Next(next)
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
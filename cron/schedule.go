@@ -0,0 +1,206 @@
+// Copyright 2025 Sentinéz Labs.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"slices"
+	"time"
+)
+
+// maxBlackoutHops bounds how many adjacent blackouts Next walks through
+// before giving up, so a misconfigured calendar cannot spin forever.
+const maxBlackoutHops = 1024
+
+// Policy decides what happens to a run that falls inside a blackout.
+type Policy int
+
+const (
+	// PolicySkip drops the run and waits for the first interval tick after
+	// the blackout ends.
+	PolicySkip Policy = iota
+
+	// PolicyDefer postpones the run to the moment the blackout ends.
+	PolicyDefer
+)
+
+// Window is a recurring time-of-day range on selected weekdays.
+// From and To are offsets since midnight. A To at or before From wraps
+// past midnight, e.g. 22h-06h. An empty Weekdays matches every day; for a
+// wrapping window the weekday is the one the window starts on.
+type Window struct {
+	Weekdays []time.Weekday
+	From     time.Duration
+	To       time.Duration
+}
+
+// Blackout is a set of periods in which jobs must not run.
+// Windows and Dates are evaluated in Location, which defaults to UTC.
+// Dates are matched by their calendar day in Location and block the whole
+// day.
+type Blackout struct {
+	Location *time.Location
+	Windows  []Window
+	Dates    []time.Time
+}
+
+// Schedule runs a job every Interval, honoring the Blackout per Policy.
+type Schedule struct {
+	Interval time.Duration
+	Blackout Blackout
+	Policy   Policy
+}
+
+// Every returns a schedule that runs every interval without blackouts.
+func Every(interval time.Duration) Schedule {
+	return Schedule{Interval: interval}
+}
+
+// Next returns the next run time strictly after t.
+// It returns the zero time if no run can be found, e.g. when the
+// blackout covers every tick.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.Interval <= 0 {
+		return time.Time{}
+	}
+
+	return s.settle(t, t.Add(s.Interval))
+}
+
+// first returns the first run time at or after t: t itself unless it is
+// blacked out, the blackout end under PolicyDefer, or Next under
+// PolicySkip.
+func (s Schedule) first(t time.Time) time.Time {
+	if _, ok := s.Blackout.Until(t); !ok {
+		return t
+	}
+
+	if s.Policy == PolicyDefer {
+		return s.settle(t, t)
+	}
+
+	return s.Next(t)
+}
+
+// settle returns next, or the first candidate after the blackouts
+// covering it.
+func (s Schedule) settle(t, next time.Time) time.Time {
+	for range maxBlackoutHops {
+		until, ok := s.Blackout.Until(next)
+		if !ok {
+			return next
+		}
+
+		next = s.resume(t, until)
+	}
+
+	return time.Time{}
+}
+
+// resume returns the first candidate at or after the blackout end.
+func (s Schedule) resume(t, until time.Time) time.Time {
+	if s.Policy == PolicyDefer {
+		return until
+	}
+
+	ticks := until.Sub(t) / s.Interval
+	next := t.Add(ticks * s.Interval)
+	if next.Before(until) {
+		next = next.Add(s.Interval)
+	}
+
+	return next
+}
+
+// Until reports whether t falls inside the blackout and, if so, the time
+// the blackout containing t ends.
+func (b Blackout) Until(t time.Time) (time.Time, bool) {
+	local := t.In(b.location())
+	var (
+		end time.Time
+		ok  bool
+	)
+
+	if b.hasDate(local) {
+		end, ok = midnight(local).AddDate(0, 0, 1), true
+	}
+
+	for _, w := range b.Windows {
+		if until, in := w.until(local); in && until.After(end) {
+			end, ok = until, true
+		}
+	}
+
+	return end, ok
+}
+
+func (b Blackout) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+
+	return b.Location
+}
+
+// hasDate compares calendar days in the blackout's location.
+func (b Blackout) hasDate(t time.Time) bool {
+	y, m, d := t.In(b.location()).Date()
+	return slices.ContainsFunc(b.Dates, func(date time.Time) bool {
+		dy, dm, dd := date.In(b.location()).Date()
+		return dy == y && dm == m && dd == d
+	})
+}
+
+// until checks the window opened today and, for wrapping windows, the one
+// opened yesterday.
+func (w Window) until(t time.Time) (time.Time, bool) {
+	today := midnight(t)
+	for _, start := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if !w.matches(start.Weekday()) {
+			continue
+		}
+
+		from, to := clock(start, w.From), clock(start, w.To)
+		if w.To <= w.From {
+			to = clock(start.AddDate(0, 0, 1), w.To)
+		}
+
+		if !t.Before(from) && t.Before(to) {
+			return to, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (w Window) matches(wd time.Weekday) bool {
+	return len(w.Weekdays) == 0 || slices.Contains(w.Weekdays, wd)
+}
+
+// clock returns the wall clock time off since the midnight of the day of
+// t, in t's location. Unlike t.Add(off), it stays right on days with a
+// DST transition.
+func clock(t time.Time, off time.Duration) time.Time {
+	y, m, d := t.Date()
+	h, rest := off/time.Hour, off%time.Hour
+	mins, rest := rest/time.Minute, rest%time.Minute
+	sec, nsec := rest/time.Second, rest%time.Second
+	return time.Date(y, m, d, int(h), int(mins), int(sec), int(nsec),
+		t.Location())
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Sentinéz Labs.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	// Embeds the zoneinfo of mustLoad, missing from minimal images.
+	_ "time/tzdata"
+)
+
+// businessHours blocks 09:00-18:00 on weekdays.
+var businessHours = Window{
+	Weekdays: []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday,
+		time.Thursday, time.Friday,
+	},
+	From: 9 * time.Hour,
+	To:   18 * time.Hour,
+}
+
+// newYork observes DST, from 2025-03-09 02:00 to 2025-11-02 02:00.
+var newYork = mustLoad("America/New_York")
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// nolint:funlen
+func TestScheduleNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from     string
+		want     string
+	}{
+		{
+			name:     "No blackout",
+			schedule: Every(time.Hour),
+			from:     "2025-06-02T08:30:00Z",
+			want:     "2025-06-02T09:30:00Z",
+		},
+		{
+			name: "Skip business hours",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{Windows: []Window{businessHours}},
+			},
+			from: "2025-06-02T08:30:00Z",
+			want: "2025-06-02T18:30:00Z",
+		},
+		{
+			name: "Defer business hours",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{Windows: []Window{businessHours}},
+				Policy:   PolicyDefer,
+			},
+			from: "2025-06-02T08:30:00Z",
+			want: "2025-06-02T18:00:00Z",
+		},
+		{
+			name: "Weekend is not blacked out",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{Windows: []Window{businessHours}},
+			},
+			from: "2025-06-07T08:30:00Z",
+			want: "2025-06-07T09:30:00Z",
+		},
+		{
+			name: "Window wraps past midnight",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{Windows: []Window{
+					{From: 22 * time.Hour, To: 6 * time.Hour},
+				}},
+				Policy: PolicyDefer,
+			},
+			from: "2025-06-02T21:30:00Z",
+			want: "2025-06-03T06:00:00Z",
+		},
+		{
+			name: "Freeze date",
+			schedule: Schedule{
+				Interval: 6 * time.Hour,
+				Blackout: Blackout{
+					Dates: []time.Time{at("2025-12-25T00:00:00Z")},
+				},
+			},
+			from: "2025-12-24T20:00:00Z",
+			want: "2025-12-26T02:00:00Z",
+		},
+		{
+			name: "Window on DST transition day",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{
+					Location: newYork,
+					Windows: []Window{
+						{From: 9 * time.Hour, To: 18 * time.Hour},
+					},
+				},
+				Policy: PolicyDefer,
+			},
+			from: "2025-03-09T12:30:00Z",
+			want: "2025-03-09T22:00:00Z",
+		},
+		{
+			name: "Freeze date in location",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{
+					Location: time.FixedZone("ICT", 7*3600),
+					Dates:    []time.Time{at("2025-12-24T20:00:00Z")},
+				},
+				Policy: PolicyDefer,
+			},
+			from: "2025-12-24T16:30:00Z",
+			want: "2025-12-25T17:00:00Z",
+		},
+		{
+			name: "Window in location",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: Blackout{
+					Location: time.FixedZone("ICT", 7*3600),
+					Windows:  []Window{businessHours},
+				},
+				Policy: PolicyDefer,
+			},
+			from: "2025-06-02T01:30:00Z",
+			want: "2025-06-02T11:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.Next(at(tt.from))
+			if !got.Equal(at(tt.want)) {
+				t.Errorf("Next(%s) = %v, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextFullBlackout(t *testing.T) {
+	s := Schedule{
+		Interval: time.Hour,
+		Blackout: Blackout{Windows: []Window{{From: 0, To: 0}}},
+	}
+
+	if got := s.Next(at("2025-06-02T08:30:00Z")); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+// nolint:funlen
+func TestScheduleFirst(t *testing.T) {
+	blackout := Blackout{Windows: []Window{businessHours}}
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from     string
+		want     string
+	}{
+		{
+			name:     "Not blacked out",
+			schedule: Schedule{Interval: time.Hour, Blackout: blackout},
+			from:     "2025-06-02T08:30:00Z",
+			want:     "2025-06-02T08:30:00Z",
+		},
+		{
+			name: "Deferred to blackout end",
+			schedule: Schedule{
+				Interval: time.Hour,
+				Blackout: blackout,
+				Policy:   PolicyDefer,
+			},
+			from: "2025-06-02T10:15:00Z",
+			want: "2025-06-02T18:00:00Z",
+		},
+		{
+			name:     "Skipped to next tick",
+			schedule: Schedule{Interval: time.Hour, Blackout: blackout},
+			from:     "2025-06-02T10:15:00Z",
+			want:     "2025-06-02T18:15:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.schedule.first(at(tt.from))
+			if !got.Equal(at(tt.want)) {
+				t.Errorf("first(%s) = %v, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
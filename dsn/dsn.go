@@ -0,0 +1,294 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dsn parses and validates the database URIs of the service
+// configuration.
+package dsn
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS modes, following the libpq sslmode names.
+const (
+	TLSDisable    = "disable"
+	TLSAllow      = "allow"
+	TLSPrefer     = "prefer"
+	TLSRequire    = "require"
+	TLSVerifyCA   = "verify-ca"
+	TLSVerifyFull = "verify-full"
+)
+
+var (
+	// PostgresSchemes are the accepted PostgreSQL and TimescaleDB schemes.
+	PostgresSchemes = []string{"postgres", "postgresql"}
+
+	// ClickhouseSchemes are the accepted ClickHouse schemes.
+	ClickhouseSchemes = []string{"clickhouse", "tcp", "http", "https"}
+
+	tlsModes = []string{TLSDisable, TLSAllow, TLSPrefer, TLSRequire,
+		TLSVerifyCA, TLSVerifyFull}
+)
+
+// ErrInvalid is wrapped by every parse error.
+var ErrInvalid = errors.New("invalid DSN")
+
+// DSN is a parsed database URI.
+type DSN struct {
+	// Env is the environment variable the URI was read from.
+	Env string
+
+	Scheme   string
+	User     string
+	Password string
+	Hosts    []string
+	Database string
+	TLSMode  string
+	Params   url.Values
+
+	// Pool parameters, zero when not set in the URI.
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+
+	raw string
+}
+
+// Raw returns the URI as configured, password included.
+func (d *DSN) Raw() string {
+	return d.raw
+}
+
+// Parse parses raw, read from env, accepting one of schemes.
+// Errors name env and never include the password.
+func Parse(env, raw string, schemes ...string) (*DSN, error) {
+	if raw == "" {
+		return nil, invalid(env, "value is empty")
+	}
+
+	d, err := split(env, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schemes) > 0 && !slices.Contains(schemes, d.Scheme) {
+		return nil, invalid(env, "scheme %q is not one of %s", d.Scheme,
+			strings.Join(schemes, "|"))
+	}
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// String returns the URI with its password masked.
+func (d *DSN) String() string {
+	var sb strings.Builder
+	sb.WriteString(d.Scheme + "://")
+	if d.User != "" || d.Password != "" {
+		sb.WriteString(url.User(d.User).String())
+		if d.Password != "" {
+			sb.WriteString(":xxxxx")
+		}
+		sb.WriteString("@")
+	}
+
+	sb.WriteString(strings.Join(d.Hosts, ","))
+	sb.WriteString("/" + url.PathEscape(d.Database))
+	if len(d.Params) > 0 {
+		sb.WriteString("?" + d.Params.Encode())
+	}
+
+	return sb.String()
+}
+
+// split breaks raw into its parts. It does not use url.Parse, which
+// rejects the comma-separated host lists libpq accepts.
+func split(env, raw string) (*DSN, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok || scheme == "" {
+		return nil, invalid(env, "missing scheme")
+	}
+
+	rest, query, _ := strings.Cut(rest, "?")
+	authority, path, _ := strings.Cut(rest, "/")
+
+	d := &DSN{Env: env, Scheme: strings.ToLower(scheme), raw: raw}
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		if err := d.setUser(authority[:i]); err != nil {
+			return nil, err
+		}
+		authority = authority[i+1:]
+	}
+
+	if authority != "" {
+		d.Hosts = strings.Split(authority, ",")
+	}
+
+	var err error
+	if d.Database, err = url.PathUnescape(path); err != nil {
+		return nil, invalid(env, "malformed database name")
+	}
+
+	if d.Params, err = url.ParseQuery(query); err != nil {
+		return nil, invalid(env, "malformed query parameters")
+	}
+
+	return d, nil
+}
+
+func (d *DSN) setUser(userinfo string) error {
+	user, pass, _ := strings.Cut(userinfo, ":")
+
+	var err error
+	if d.User, err = url.PathUnescape(user); err != nil {
+		return invalid(d.Env, "malformed user")
+	}
+
+	if d.Password, err = url.PathUnescape(pass); err != nil {
+		return invalid(d.Env, "malformed password")
+	}
+
+	return nil
+}
+
+func (d *DSN) validate() error {
+	if len(d.Hosts) == 0 || slices.Contains(d.Hosts, "") {
+		return invalid(d.Env, "missing host")
+	}
+
+	for _, host := range d.Hosts {
+		if err := validPort(host); err != nil {
+			return invalid(d.Env, "host %q: %v", host, err)
+		}
+	}
+
+	if d.Database == "" {
+		return invalid(d.Env, "missing database")
+	}
+
+	d.TLSMode = d.tlsMode()
+	if !slices.Contains(tlsModes, d.TLSMode) {
+		return invalid(d.Env, "unknown TLS mode %q", d.TLSMode)
+	}
+
+	return d.parsePool()
+}
+
+// tlsMode reads sslmode, or the secure flag and https scheme ClickHouse
+// uses. It defaults to prefer, as libpq does.
+func (d *DSN) tlsMode() string {
+	if mode := d.Params.Get("sslmode"); mode != "" {
+		return mode
+	}
+
+	if secure, _ := strconv.ParseBool(d.Params.Get("secure")); secure ||
+		d.Scheme == "https" {
+		return TLSRequire
+	}
+
+	if d.Params.Has("secure") || d.Scheme == "http" {
+		return TLSDisable
+	}
+
+	return TLSPrefer
+}
+
+func (d *DSN) parsePool() error {
+	var err error
+	if d.MaxConns, err = d.conns("pool_max_conns", 1); err != nil {
+		return err
+	}
+
+	if d.MinConns, err = d.conns("pool_min_conns", 0); err != nil {
+		return err
+	}
+
+	if d.MaxConns > 0 && d.MinConns > d.MaxConns {
+		return invalid(d.Env, "pool_min_conns exceeds pool_max_conns")
+	}
+
+	durations := []struct {
+		key string
+		dst *time.Duration
+	}{
+		{"pool_max_conn_lifetime", &d.MaxConnLifetime},
+		{"pool_max_conn_idle_time", &d.MaxConnIdleTime},
+		{"pool_health_check_period", &d.HealthCheckPeriod},
+	}
+	for _, p := range durations {
+		if *p.dst, err = d.duration(p.key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DSN) conns(key string, lowest int32) (int32, error) {
+	raw := d.Params.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || int32(n) < lowest {
+		return 0, invalid(d.Env, "%s must be an integer >= %d", key, lowest)
+	}
+
+	return int32(n), nil
+}
+
+func (d *DSN) duration(key string) (time.Duration, error) {
+	raw := d.Params.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	v, err := time.ParseDuration(raw)
+	if err != nil || v <= 0 {
+		return 0, invalid(d.Env, "%s must be a positive duration", key)
+	}
+
+	return v, nil
+}
+
+func validPort(host string) error {
+	i := strings.LastIndex(host, ":")
+	if i < 0 || strings.HasSuffix(host, "]") {
+		return nil
+	}
+
+	port, err := strconv.ParseUint(host[i+1:], 10, 16)
+	if err != nil || port == 0 {
+		return fmt.Errorf("invalid port %q", host[i+1:])
+	}
+
+	return nil
+}
+
+func invalid(env, format string, args ...any) error {
+	return fmt.Errorf("%s: %w: %s", env, ErrInvalid,
+		fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	"github.com/sentinez/shared/config"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Pool defaults applied when the URI does not set them.
+const (
+	DefaultMaxConns          = 10
+	DefaultMaxConnLifetime   = time.Hour
+	DefaultMaxConnIdleTime   = 5 * time.Minute
+	DefaultHealthCheckPeriod = 30 * time.Second
+	DefaultStatementTimeout  = 30 * time.Second
+)
+
+// statementTimeout is the server parameter bounding query duration.
+const statementTimeout = "statement_timeout"
+
+var envFields = (&settingpb.EnvConfig{}).ProtoReflect().Descriptor().Fields()
+
+// Postgres parses the PostgreSQL URI of conf.
+func Postgres(conf *settingpb.EnvConfig) (*DSN, error) {
+	return Parse(envName("postgres_uri"), conf.GetPostgresUri(),
+		PostgresSchemes...)
+}
+
+// Timescale parses the TimescaleDB URI of conf.
+func Timescale(conf *settingpb.EnvConfig) (*DSN, error) {
+	return Parse(envName("timescale_uri"), conf.GetTimescaleUri(),
+		PostgresSchemes...)
+}
+
+// Clickhouse parses the ClickHouse URI of conf.
+func Clickhouse(conf *settingpb.EnvConfig) (*DSN, error) {
+	return Parse(envName("clickhouse_uri"), conf.GetClickhouseUri(),
+		ClickhouseSchemes...)
+}
+
+// NewPool builds a pgx pool for d and pings it. Pool parameters missing
+// from the URI fall back to the Default values, and queries are bounded
+// by DefaultStatementTimeout unless the URI sets statement_timeout.
+func NewPool(ctx context.Context, d *DSN) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(d.raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", d.Env, ErrInvalid, err)
+	}
+
+	applyDefaults(cfg, d)
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.Env, err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("%s: error connecting to %s: %w",
+			d.Env, d, err)
+	}
+
+	return pool, nil
+}
+
+// PostgresPool builds a pool for the PostgreSQL URI of conf.
+func PostgresPool(ctx context.Context,
+	conf *settingpb.EnvConfig) (*pgxpool.Pool, error) {
+	d, err := Postgres(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPool(ctx, d)
+}
+
+// TimescalePool builds a pool for the TimescaleDB URI of conf.
+func TimescalePool(ctx context.Context,
+	conf *settingpb.EnvConfig) (*pgxpool.Pool, error) {
+	d, err := Timescale(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPool(ctx, d)
+}
+
+func applyDefaults(cfg *pgxpool.Config, d *DSN) {
+	if d.MaxConns == 0 {
+		cfg.MaxConns = DefaultMaxConns
+	}
+
+	if d.MaxConnLifetime == 0 {
+		cfg.MaxConnLifetime = DefaultMaxConnLifetime
+	}
+
+	if d.MaxConnIdleTime == 0 {
+		cfg.MaxConnIdleTime = DefaultMaxConnIdleTime
+	}
+
+	if d.HealthCheckPeriod == 0 {
+		cfg.HealthCheckPeriod = DefaultHealthCheckPeriod
+	}
+
+	params := cfg.ConnConfig.RuntimeParams
+	if _, ok := params[statementTimeout]; !ok {
+		params[statementTimeout] = strconv.FormatInt(
+			DefaultStatementTimeout.Milliseconds(), 10)
+	}
+}
+
+func envName(field protoreflect.Name) string {
+	return config.EnvName(config.Prefix, envFields.ByName(field))
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/spf13/pflag"
+)
+
+// rootName stands for the binary in usage lines.
+const rootName = "<service>"
+
+// Command is a subcommand of a service binary, e.g. serve or migrate.
+// Commands nest, so config check is a check command under config.
+type Command struct {
+	// Name is the word selecting the command.
+	Name string
+
+	// Usage is a one-line description shown in help.
+	Usage string
+
+	// Flags registers the flags of the command. The flags of its parent
+	// commands and the global flags of the parser are added after it.
+	Flags func(fs *pflag.FlagSet)
+
+	// Run handles the command with the remaining positional args. A
+	// command without Run only groups its subcommands.
+	Run func(args []string) error
+
+	// Commands are the subcommands.
+	Commands []*Command
+
+	// Hidden leaves the command out of help and completion.
+	Hidden bool
+
+	fs *pflag.FlagSet
+}
+
+// FlagSet returns the flags of c once it has been selected, e.g. for
+// config.Flags.
+func (c *Command) FlagSet() *pflag.FlagSet {
+	return c.fs
+}
+
+// newFlagSet returns the flags of c followed by the flags of its parent.
+func (c *Command) newFlagSet(parent *pflag.FlagSet) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(c.Name, pflag.ContinueOnError)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	fs.AddFlagSet(parent)
+
+	return fs
+}
+
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) printUsage(w io.Writer, meta *typepb.XMeta,
+	name string) {
+	var sb strings.Builder
+	sb.WriteString(info(meta))
+
+	if len(c.Commands) == 0 {
+		fmt.Fprintf(&sb, "Usage: %s [Flags]\n", name)
+	} else {
+		fmt.Fprintf(&sb, "Usage: %s <command> [Flags]\n\nCommands:\n", name)
+		tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		for _, sub := range c.Commands {
+			if sub.Hidden {
+				continue
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Usage)
+		}
+		_ = tw.Flush()
+	}
+
+	fmt.Fprintf(&sb, "\nFlags:\n%s", c.fs.FlagUsages())
+	_, _ = io.WriteString(w, sb.String())
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commandRun records what a command tree built by newCommands ran.
+type commandRun struct {
+	ran    string
+	args   []string
+	dryRun bool
+	file   string
+}
+
+// newCommands returns serve and config, with a check subcommand under
+// config, recording their runs in r.
+func newCommands(r *commandRun) (config, check *Command) {
+	check = &Command{
+		Name:  "check",
+		Usage: "validate the configuration",
+		Flags: func(fs *pflag.FlagSet) {
+			fs.BoolVar(&r.dryRun, "dry-run", false, "only print")
+		},
+		Run: func(args []string) error {
+			r.ran, r.args = "config check", args
+			return nil
+		},
+	}
+	config = &Command{
+		Name: "config",
+		Flags: func(fs *pflag.FlagSet) {
+			fs.StringVar(&r.file, "file", "", "config file")
+		},
+		Commands: []*Command{check},
+	}
+
+	return config, check
+}
+
+func TestCommandExecute(t *testing.T) {
+	var r commandRun
+	config, check := newCommands(&r)
+	serve := &Command{Name: "serve", Run: func([]string) error {
+		r.ran = "serve"
+		return nil
+	}}
+
+	p := NewParser(nil)
+	p.SetOutput(&strings.Builder{})
+	err := p.Execute([]string{
+		"-m", "prod", "config", "check", "a", "--dry-run", "--mode=sandbox",
+		"--file=c.yaml",
+	}, serve, config)
+	require.NoError(t, err)
+
+	assert.Equal(t, "config check", r.ran)
+	assert.Equal(t, []string{"a"}, r.args)
+	assert.True(t, r.dryRun)
+	assert.Equal(t, "c.yaml", r.file)
+	assert.Equal(t, "sandbox", p.Flags().GetEnvMode())
+	assert.NotNil(t, check.FlagSet().Lookup("mode"))
+
+	require.NoError(t, p.Execute([]string{"serve"}, serve, config))
+	assert.Equal(t, "serve", r.ran)
+}
+
+func TestCommandSelectErrors(t *testing.T) {
+	config, _ := newCommands(&commandRun{})
+
+	p := NewParser(nil)
+	p.SetOutput(&strings.Builder{})
+
+	_, _, err := p.Select([]string{"deploy"}, config)
+	assert.ErrorContains(t, err, `unknown command "deploy"`)
+
+	_, _, err = p.Select([]string{"config"}, config)
+	assert.ErrorContains(t, err, "<service> config: missing command")
+}
+
+func TestExecuteParsed(t *testing.T) {
+	t.Cleanup(func() { once = sync.Once{} })
+	once.Do(func() {})
+
+	assert.ErrorIs(t, Execute(nil), ErrParsed)
+}
+
+func TestCommandUsage(t *testing.T) {
+	c := &Command{
+		Commands: []*Command{
+			{Name: "serve", Usage: "run the service"},
+			{Name: "migrate", Usage: "apply migrations"},
+		},
+		fs: pflag.NewFlagSet("root", pflag.ContinueOnError),
+	}
+	c.fs.String("env", "dev", "run mode")
+
+	var sb strings.Builder
+	c.printUsage(&sb, &typepb.XMeta{}, rootName)
+
+	out := sb.String()
+	assert.Contains(t, out, "Usage: <service> <command> [Flags]")
+	assert.Contains(t, out, "  serve    run the service\n")
+	assert.Contains(t, out, "  migrate  apply migrations\n")
+	assert.Contains(t, out, "--env string")
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Shells WriteCompletion supports.
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// completion names the hidden command printing completion scripts.
+const completion = "completion"
+
+// choicesAnnotation is the flag annotation holding its accepted values.
+const choicesAnnotation = "flagx_choices"
+
+// nonIdent matches the characters not allowed in shell function names.
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Choices sets the values completed for the flag name of fs.
+func Choices(fs *pflag.FlagSet, name string, values ...string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("no such flag: %s", name)
+	}
+
+	setChoices(f, values...)
+	return nil
+}
+
+func setChoices(f *pflag.Flag, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+
+	if f.Annotations == nil {
+		f.Annotations = map[string][]string{}
+	}
+	f.Annotations[choicesAnnotation] = values
+}
+
+// WriteCompletion writes a bash, zsh or fish completion script for prog
+// covering cmds and all flags, with the choices of enumerated flags.
+func (p *Parser) WriteCompletion(w io.Writer, shell, prog string,
+	cmds ...*Command) error {
+	nodes := p.completionTree(cmds)
+
+	var script string
+	switch shell {
+	case ShellBash:
+		script = bashCompletion(prog, nodes)
+	case ShellZsh:
+		script = zshCompletion(prog, nodes)
+	case ShellFish:
+		script = fishCompletion(prog, nodes)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// completionCommand returns the hidden completion <shell> command.
+func (p *Parser) completionCommand(cmds []*Command) *Command {
+	return &Command{
+		Name:   completion,
+		Usage:  "print a shell completion script (bash|zsh|fish)",
+		Hidden: true,
+		Run: func(args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("usage: %s bash|zsh|fish", completion)
+			}
+
+			prog := filepath.Base(os.Args[0])
+			return p.WriteCompletion(p.out, args[0], prog, cmds...)
+		},
+	}
+}
+
+// compNode is a command of the completion tree. path holds the command
+// names leading to it joined with spaces, empty for the root.
+type compNode struct {
+	path  string
+	subs  []*Command
+	flags []*pflag.Flag
+}
+
+func (p *Parser) completionTree(cmds []*Command) []compNode {
+	var nodes []compNode
+	var walk func(cmds []*Command, path string, fs *pflag.FlagSet)
+	walk = func(cmds []*Command, path string, fs *pflag.FlagSet) {
+		node := compNode{path: path}
+		fs.VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				node.flags = append(node.flags, f)
+			}
+		})
+
+		for _, c := range cmds {
+			if !c.Hidden {
+				node.subs = append(node.subs, c)
+			}
+		}
+		nodes = append(nodes, node)
+
+		for _, c := range node.subs {
+			walk(c.Commands, strings.TrimSpace(path+" "+c.Name),
+				c.newFlagSet(fs))
+		}
+	}
+	walk(cmds, "", p.fs)
+
+	return nodes
+}
+
+// transitions returns the case patterns moving from a command path to a
+// subcommand, as "path/name".
+func transitions(nodes []compNode) []string {
+	var out []string
+	for _, n := range nodes {
+		for _, c := range n.subs {
+			out = append(out, fmt.Sprintf("%q", n.path+"/"+c.Name))
+		}
+	}
+
+	return out
+}
+
+// flagArg tells whether f takes no value, an optional or a required one.
+func flagArg(f *pflag.Flag) (takes, optional bool) {
+	if f.Value.Type() == "bool" {
+		return false, false
+	}
+
+	return true, f.NoOptDefVal != ""
+}
+
+// flagUsage returns the usage of f without the env var note.
+func flagUsage(f *pflag.Flag) string {
+	usage, _, _ := strings.Cut(f.Usage, " [$")
+	return usage
+}
+
+func bashCompletion(prog string, nodes []compNode) string {
+	fn := "_" + nonIdent.ReplaceAllString(prog, "_")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# bash completion for %s\n%s() {\n", prog, fn)
+	sb.WriteString(`    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local cmd="" word i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+`)
+	writeCommandCase(&sb, nodes)
+
+	for _, n := range nodes {
+		writeBashNode(&sb, n)
+	}
+
+	fmt.Fprintf(&sb, "    esac\n}\n\ncomplete -F %s %s\n", fn, prog)
+	return sb.String()
+}
+
+// writeCommandCase ends the bash and zsh loop over the words before the
+// cursor, which sets $cmd to their command path, and opens the case on
+// it.
+func writeCommandCase(sb *strings.Builder, nodes []compNode) {
+	sb.WriteString("        case \"$cmd/$word\" in\n")
+	if trans := transitions(nodes); len(trans) > 0 {
+		fmt.Fprintf(sb, "        %s) cmd=\"${cmd:+$cmd }$word\" ;;\n",
+			strings.Join(trans, "|"))
+	}
+	sb.WriteString("        esac\n    done\n\n    case \"$cmd\" in\n")
+}
+
+// writeBashNode writes the bash case of n, completing the value of the
+// flag before the cursor, or else the subcommands and flags of n.
+func writeBashNode(sb *strings.Builder, n compNode) {
+	fmt.Fprintf(sb, "    %q)\n        case \"$prev\" in\n", n.path)
+
+	var words []string
+	for _, c := range n.subs {
+		words = append(words, c.Name)
+	}
+
+	for _, f := range n.flags {
+		names := []string{"--" + f.Name}
+		if f.Shorthand != "" {
+			names = append(names, "-"+f.Shorthand)
+		}
+		words = append(words, names...)
+		writeBashFlag(sb, f, names)
+	}
+
+	fmt.Fprintf(sb, "        esac\n"+
+		"        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n        ;;\n",
+		strings.Join(words, " "))
+}
+
+// writeBashFlag writes the bash case completing the value of f, named
+// names, when it requires one.
+func writeBashFlag(sb *strings.Builder, f *pflag.Flag, names []string) {
+	takes, optional := flagArg(f)
+	if !takes || optional {
+		return
+	}
+
+	reply := `compgen -f -- "$cur"`
+	if choices := f.Annotations[choicesAnnotation]; len(choices) > 0 {
+		reply = fmt.Sprintf("compgen -W %q -- \"$cur\"",
+			strings.Join(choices, " "))
+	}
+	fmt.Fprintf(sb, "        %s)\n            COMPREPLY=($(%s))\n"+
+		"            return\n            ;;\n",
+		strings.Join(names, "|"), reply)
+}
+
+func zshCompletion(prog string, nodes []compNode) string {
+	fn := "_" + nonIdent.ReplaceAllString(prog, "_")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#compdef %s\n\n%s() {\n", prog, fn)
+	sb.WriteString(`    local cmd="" word i
+    for ((i = 2; i < CURRENT; i++)); do
+        word="${words[i]}"
+`)
+	writeCommandCase(&sb, nodes)
+
+	for _, n := range nodes {
+		writeZshNode(&sb, n)
+	}
+
+	fmt.Fprintf(&sb, "    esac\n}\n\ncompdef %s %s\n", fn, prog)
+	return sb.String()
+}
+
+// writeZshNode writes the zsh case of n, completing its flags and
+// subcommands, or files when it has none.
+func writeZshNode(sb *strings.Builder, n compNode) {
+	fmt.Fprintf(sb, "    %q)\n        _arguments -s", n.path)
+	for _, f := range n.flags {
+		fmt.Fprintf(sb, " \\\n            %s", zshFlag(f))
+	}
+
+	positional := "'*: :_files'"
+	if len(n.subs) > 0 {
+		var subs []string
+		for _, c := range n.subs {
+			usage := strings.NewReplacer(`'`, `'\''`, `:`, `\:`,
+				`"`, `\"`).Replace(c.Usage)
+			subs = append(subs, fmt.Sprintf(`%s\:"%s"`, c.Name, usage))
+		}
+		positional = "'*: :((" + strings.Join(subs, " ") + "))'"
+	}
+	fmt.Fprintf(sb, " \\\n            %s\n        ;;\n", positional)
+}
+
+// zshFlag returns the _arguments spec of f.
+func zshFlag(f *pflag.Flag) string {
+	spec := "[" + zshEscape(flagUsage(f)) + "]"
+	if takes, optional := flagArg(f); takes {
+		action := "_files"
+		if choices := f.Annotations[choicesAnnotation]; len(choices) > 0 {
+			action = "(" + strings.Join(choices, " ") + ")"
+		}
+
+		sep := ":"
+		if optional {
+			sep = "::"
+		}
+		spec += sep + f.Name + ":" + action
+	}
+
+	if f.Shorthand == "" {
+		return "'--" + f.Name + spec + "'"
+	}
+
+	return fmt.Sprintf("'(-%s --%s)'{-%s,--%s}'%s'",
+		f.Shorthand, f.Name, f.Shorthand, f.Name, spec)
+}
+
+// zshEscape escapes text for a single-quoted _arguments description.
+func zshEscape(text string) string {
+	return strings.NewReplacer(
+		`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`,
+	).Replace(text)
+}
+
+func fishCompletion(prog string, nodes []compNode) string {
+	fn := "__" + nonIdent.ReplaceAllString(prog, "_") + "_path"
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# fish completion for %s\n", prog)
+	fmt.Fprintf(&sb, "function %s\n", fn)
+	sb.WriteString(`    set -l cmd ""
+    set -l words (commandline -opc)
+    set -e words[1]
+    for word in $words
+`)
+	if trans := transitions(nodes); len(trans) > 0 {
+		fmt.Fprintf(&sb, "        switch \"$cmd/$word\"\n"+
+			"            case %s\n"+
+			"                set cmd (string trim -- \"$cmd $word\")\n"+
+			"        end\n", strings.Join(trans, " "))
+	}
+	sb.WriteString("    end\n    test \"$cmd\" = \"$argv[1]\"\nend\n\n")
+	fmt.Fprintf(&sb, "complete -c %s -f\n", prog)
+
+	for _, n := range nodes {
+		cond := fishQuote(fmt.Sprintf("%s %q", fn, n.path))
+		for _, c := range n.subs {
+			fmt.Fprintf(&sb, "complete -c %s -n %s -a %s -d %s\n",
+				prog, cond, c.Name, fishQuote(c.Usage))
+		}
+
+		for _, f := range n.flags {
+			writeFishFlag(&sb, prog, cond, f)
+		}
+	}
+
+	return sb.String()
+}
+
+// writeFishFlag writes the fish completion of f under the condition
+// cond.
+func writeFishFlag(sb *strings.Builder, prog, cond string, f *pflag.Flag) {
+	fmt.Fprintf(sb, "complete -c %s -n %s -l %s", prog, cond, f.Name)
+	if f.Shorthand != "" {
+		fmt.Fprintf(sb, " -s %s", f.Shorthand)
+	}
+	fmt.Fprintf(sb, " -d %s", fishQuote(flagUsage(f)))
+
+	takes, optional := flagArg(f)
+	choices := f.Annotations[choicesAnnotation]
+	switch {
+	case takes && len(choices) > 0 && !optional:
+		fmt.Fprintf(sb, " -x -a %s", fishQuote(strings.Join(choices, " ")))
+	case takes && len(choices) > 0:
+		fmt.Fprintf(sb, " -a %s", fishQuote(strings.Join(choices, " ")))
+	case takes && !optional:
+		sb.WriteString(" -r -F")
+	}
+	sb.WriteString("\n")
+}
+
+// fishQuote single-quotes text for fish.
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(text) + "'"
+}
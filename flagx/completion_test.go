@@ -0,0 +1,125 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func completionCommands() []*Command {
+	return []*Command{
+		{Name: "serve", Usage: "run the service", Run: func([]string) error {
+			return nil
+		}},
+		{Name: "config", Usage: "config tools", Commands: []*Command{
+			{
+				Name:  "check",
+				Usage: "validate: the configuration",
+				Flags: func(fs *pflag.FlagSet) {
+					fs.String("format", "text", "report format")
+					_ = Choices(fs, "format", "text", "json")
+				},
+				Run: func([]string) error { return nil },
+			},
+		}},
+		{Name: "internal", Hidden: true},
+	}
+}
+
+// completionWant holds lines expected in the completion script of each
+// shell for completionCommands.
+var completionWant = map[string][]string{
+	ShellBash: {
+		`"/serve"|"/config"|"config/check") cmd=`,
+		"--mode|-m)\n            COMPREPLY=($(compgen " +
+			`-W "dev prod sandbox" -- "$cur"))`,
+		`    "config check")`,
+		`--format)`,
+		"complete -F _senz_svc senz-svc\n",
+	},
+	ShellZsh: {
+		"#compdef senz-svc\n",
+		`'(-m --mode)'{-m,--mode}'[run mode (dev|prod|sandbox)]` +
+			`:mode:(dev prod sandbox)'`,
+		`'--print-config[print the effective config and exit ` +
+			`(text|json)]::print-config:(text json)'`,
+		`'--version[print build info and exit]'`,
+		`'*: :((serve\:"run the service" config\:"config tools"))'`,
+		`check\:"validate\: the configuration"`,
+		"compdef _senz_svc senz-svc\n",
+	},
+	ShellFish: {
+		`case "/serve" "/config" "config/check"`,
+		`complete -c senz-svc -n '__senz_svc_path ""' -a serve ` +
+			`-d 'run the service'`,
+		`complete -c senz-svc -n '__senz_svc_path ""' -l mode -s m ` +
+			`-d 'run mode (dev|prod|sandbox)' -x -a 'dev prod sandbox'`,
+		`complete -c senz-svc -n '__senz_svc_path "config check"' ` +
+			`-l format -d 'report format' -x -a 'text json'`,
+	},
+}
+
+func TestWriteCompletion(t *testing.T) {
+	for shell, want := range completionWant {
+		t.Run(shell, func(t *testing.T) {
+			var sb strings.Builder
+			p := NewParser(nil)
+			require.NoError(t, p.WriteCompletion(&sb, shell, "senz-svc",
+				completionCommands()...))
+
+			out := sb.String()
+			for _, line := range want {
+				assert.Contains(t, out, line)
+			}
+			assert.NotContains(t, out, "internal")
+			assert.NotContains(t, out, "SENZ_MODE")
+		})
+	}
+
+	err := NewParser(nil).WriteCompletion(&strings.Builder{}, "tcsh", "svc")
+	assert.ErrorContains(t, err, `unsupported shell "tcsh"`)
+}
+
+func TestCompletionCommand(t *testing.T) {
+	var sb strings.Builder
+	p := NewParser(nil)
+	p.SetOutput(&sb)
+
+	cmds := completionCommands()
+	require.NoError(t, p.Execute([]string{"completion", "bash"}, cmds...))
+	assert.Contains(t, sb.String(), "complete -F")
+
+	sb.Reset()
+	err := p.Execute([]string{"--help"}, cmds...)
+	assert.ErrorIs(t, err, ErrHelp)
+	assert.Contains(t, sb.String(), "serve")
+	assert.NotContains(t, sb.String(), completion)
+}
+
+func TestParseCompletion(t *testing.T) {
+	var sb strings.Builder
+	p := NewParser(nil)
+	p.SetOutput(&sb)
+
+	err := p.Parse([]string{"completion", "fish"})
+	assert.ErrorIs(t, err, ErrCompletion)
+	assert.Contains(t, sb.String(), "-l mode -s m")
+	assert.NotContains(t, sb.String(), "-a completion")
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// EnvPrefix is the prefix of env vars bound to flags, the same as the
+// service configuration uses.
+const EnvPrefix = "SENZ"
+
+// envAnnotation is the flag annotation holding the bound env var.
+const envAnnotation = "flagx_env"
+
+// Env binds the flag name of fs to the env var key. The env var is read
+// when the flag is not passed explicitly, so the precedence is flag, env
+// var, default. The name is shown in help next to the flag.
+func Env(fs *pflag.FlagSet, name, key string) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return fmt.Errorf("no such flag: %s", name)
+	}
+
+	bindEnv(f, key)
+	return nil
+}
+
+// EnvName returns the env var name flagx derives for the flag name,
+// e.g. SENZ_LOG_LEVEL for log_level.
+func EnvName(name string) string {
+	name = strings.ReplaceAll(name, "-", "_")
+	return strings.ToUpper(EnvPrefix + "_" + name)
+}
+
+func bindEnv(f *pflag.Flag, key string) {
+	if f.Annotations == nil {
+		f.Annotations = map[string][]string{}
+	}
+
+	if _, ok := f.Annotations[envAnnotation]; !ok {
+		f.Usage += " [$" + key + "]"
+	}
+	f.Annotations[envAnnotation] = []string{key}
+}
+
+// applyEnv sets the flags of fs that were not passed from their env var.
+func applyEnv(fs *pflag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		keys := f.Annotations[envAnnotation]
+		if err != nil || f.Changed || len(keys) == 0 {
+			return
+		}
+
+		raw, ok := os.LookupEnv(keys[0])
+		if !ok || raw == "" {
+			return
+		}
+
+		if serr := f.Value.Set(raw); serr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", raw, keys[0], serr)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("SENZ_MODE", "prod")
+	t.Setenv("SENZ_LOG_LEVEL", "warn")
+	t.Setenv("SENZ_WORKERS", "x")
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	mode := fs.String("mode", "dev", "run mode")
+	level := fs.String("log_level", "debug", "log level")
+	addr := fs.String("addr", ":80", "listen address")
+	fs.Int("workers", 1, "workers")
+
+	require.NoError(t, Env(fs, "mode", EnvName("mode")))
+	require.NoError(t, Env(fs, "log_level", EnvName("log_level")))
+	require.NoError(t, Env(fs, "addr", EnvName("addr")))
+	assert.Error(t, Env(fs, "missing", "SENZ_MISSING"))
+
+	assert.Contains(t, fs.FlagUsages(), "run mode [$SENZ_MODE]")
+
+	require.NoError(t, fs.Parse([]string{"--log_level", "error"}))
+	require.NoError(t, applyEnv(fs))
+
+	assert.Equal(t, "prod", *mode, "env over default")
+	assert.Equal(t, "error", *level, "flag over env")
+	assert.Equal(t, ":80", *addr, "default without env")
+
+	require.NoError(t, Env(fs, "workers", EnvName("workers")))
+	assert.ErrorContains(t, applyEnv(fs), "SENZ_WORKERS")
+}
@@ -15,7 +15,9 @@
 package flagx
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
@@ -24,12 +26,22 @@ import (
 	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
 	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
 	"github.com/sentinez/shared/protobuf"
+	"github.com/sentinez/shared/zlog"
 	"github.com/spf13/pflag"
 	"google.golang.org/protobuf/proto"
 )
 
 var (
 	once sync.Once
+
+	// std parses the process args for Parse and Execute.
+	std *Parser
+
+	// stdout, stderr and exit are the process output and exit of Parse
+	// and Execute, replaced in tests.
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
 )
 
 // flags global variable
@@ -43,31 +55,108 @@ func info(meta *typepb.XMeta) string {
 	return console.GenFigure(service, meta.GetServiceKey())
 }
 
-// Parse flag args
-func Parse(meta *typepb.XMeta) {
+// Parse flag args. The fields of msgs are registered as flags first, see
+// Register, and the log level spec of --log_level is applied with
+// zlog.SetLevelSpec. It exits after help, --version or the completion
+// command, and with status 2 when msgs cannot be registered or the args
+// are invalid.
+func Parse(meta *typepb.XMeta, msgs ...proto.Message) {
+	exitOnError(ParseE(meta, msgs...))
+}
+
+// ParseE is Parse returning the errors instead of exiting, ErrHelp,
+// ErrVersion and ErrCompletion included. Like Parse, it only runs once.
+func ParseE(meta *typepb.XMeta, msgs ...proto.Message) error {
+	var err error
 	once.Do(func() {
+		std = newStd(meta)
+		for _, msg := range msgs {
+			if err = std.Register(msg); err != nil {
+				return
+			}
+		}
 
-		pflag.StringVarP(&flags.EnvMode, "mode", "m",
-			flags.GetEnvMode(), "run mode (dev|prod|sandbox)")
+		err = std.Parse(os.Args[1:])
+		pflag.CommandLine.AddFlagSet(std.fs)
+		if err == nil {
+			err = zlog.SetLevelSpec(flags.GetLogLevel())
+		}
+	})
 
-		pflag.StringVar(&flags.LogLevel, "log_level",
-			flags.GetLogLevel(), "log level (debug|info|warn|error)")
+	return err
+}
 
-		pflag.Usage = func() {
-			fmt.Print(info(meta))
-			fmt.Println("Usage: <service> [Flags]")
-			pflag.PrintDefaults()
-			os.Exit(0)
+// Execute parses os.Args, selects a command from cmds by the leading
+// args and runs it, see Parser.Select. Help is printed, with the banner
+// of meta, for -h, --help and commands without Run. Register service
+// flags with Register(nil, msg) before calling Execute. Like Parse, it
+// applies the --log_level spec and exits on help, --version and invalid
+// args. It returns ErrParsed when Parse or Execute already ran.
+func Execute(meta *typepb.XMeta, cmds ...*Command) error {
+	err := ErrParsed
+	once.Do(func() {
+		std = newStd(meta)
+		cmd, args, serr := std.Select(os.Args[1:], cmds...)
+		pflag.CommandLine.AddFlagSet(std.fs)
+		if serr == nil {
+			serr = zlog.SetLevelSpec(flags.GetLogLevel())
 		}
+		exitOnError(serr)
 
-		pflag.Parse()
+		err = cmd.Run(args)
 	})
+
+	return err
+}
+
+// newStd returns the parser of the process args. It parses the flags of
+// pflag.CommandLine on a flag set of its own, as pflag handles help and
+// errors of CommandLine itself, exiting without the usage of Command.
+// The flags of std are added to CommandLine once parsed, for the code
+// reading them from there, e.g. config.Flags, and pflag.Usage prints the
+// usage of std.
+func newStd(meta *typepb.XMeta) *Parser {
+	fs := pflag.NewFlagSet(rootName, pflag.ContinueOnError)
+	fs.AddFlagSet(pflag.CommandLine)
+
+	p := newParser(meta, fs, flags)
+	p.SetOutput(stdout)
+	pflag.Usage = func() {
+		(&Command{fs: fs}).printUsage(stdout, meta, rootName)
+	}
+	return p
+}
+
+// exitOnError exits with 0 for ErrHelp, ErrVersion and ErrCompletion and
+// with 2 for any other error.
+func exitOnError(err error) {
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrHelp), errors.Is(err, ErrVersion),
+		errors.Is(err, ErrCompletion):
+		exit(0)
+		return
+	}
+
+	fmt.Fprintln(stderr, err)
+	exit(2)
 }
 
 func Get() *settingpb.Flag {
 	return flags
 }
 
+// PrintConfig returns the format requested by --print-config, or an empty
+// string when the flag is not set.
+func PrintConfig() string {
+	if std == nil {
+		return ""
+	}
+
+	return std.PrintConfig()
+}
+
 // Validate used to validate flags
 func Validate(flag proto.Message) error {
 	if err := protobuf.Validate(flag); err != nil {
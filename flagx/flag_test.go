@@ -0,0 +1,155 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// process captures the output and exit code of Parse and Execute.
+type process struct {
+	out, err strings.Builder
+	code     int
+}
+
+// useProcess makes Parse and Execute run once more, on args and a fresh
+// pflag.CommandLine, writing to p and exiting by panicking with p.
+func useProcess(t *testing.T, args ...string) *process {
+	p := &process{code: -1}
+	prevArgs, prevLine := os.Args, pflag.CommandLine
+	prevOut, prevErr, prevExit := stdout, stderr, exit
+	prevUsage, prevFlags := pflag.Usage, proto.Clone(flags)
+	t.Cleanup(func() {
+		proto.Reset(flags)
+		proto.Merge(flags, prevFlags)
+		os.Args, pflag.CommandLine = prevArgs, prevLine
+		pflag.Usage = prevUsage
+		stdout, stderr, exit = prevOut, prevErr, prevExit
+		once, std = sync.Once{}, nil
+	})
+
+	os.Args = append([]string{"svc"}, args...)
+	pflag.CommandLine = pflag.NewFlagSet("svc", pflag.ExitOnError)
+	stdout, stderr = &p.out, &p.err
+	exit = func(code int) {
+		p.code = code
+		panic(p)
+	}
+	once = sync.Once{}
+
+	return p
+}
+
+// run calls f, recovering the exit of p.
+func (p *process) run(f func()) {
+	defer func() {
+		if r := recover(); r != nil && r != p {
+			panic(r)
+		}
+	}()
+
+	f()
+}
+
+func TestExecuteHelp(t *testing.T) {
+	p := useProcess(t, "--help")
+	r := &commandRun{}
+	config, _ := newCommands(r)
+	meta := &typepb.XMeta{ServiceName: "svc"}
+
+	p.run(func() { _ = Execute(meta, config) })
+
+	assert.Equal(t, 0, p.code)
+	assert.Contains(t, p.out.String(), info(meta))
+	assert.Contains(t, p.out.String(),
+		"Usage: <service> <command> [Flags]\n\nCommands:\n")
+	assert.Contains(t, p.out.String(), "  config")
+	assert.Empty(t, p.err.String())
+}
+
+func TestExecuteErrors(t *testing.T) {
+	p := useProcess(t, "--bogus")
+	p.run(func() { _ = Execute(nil, &Command{Name: "serve"}) })
+
+	assert.Equal(t, 2, p.code)
+	assert.Equal(t, "unknown flag: --bogus\n", p.err.String())
+}
+
+func TestExecuteCommandLine(t *testing.T) {
+	useProcess(t, "--name", "svc", "serve", "a")
+	name := pflag.String("name", "", "service name")
+
+	var args []string
+	require.NoError(t, Execute(nil, &Command{
+		Name: "serve",
+		Run: func(a []string) error {
+			args = a
+			return nil
+		},
+	}))
+
+	assert.Equal(t, "svc", *name)
+	assert.Equal(t, []string{"a"}, args)
+	assert.True(t, pflag.CommandLine.Changed("name"))
+	assert.NotNil(t, pflag.Lookup("mode"))
+}
+
+func TestParseHelp(t *testing.T) {
+	p := useProcess(t, "--help")
+	meta := &typepb.XMeta{ServiceName: "svc"}
+	p.run(func() { Parse(meta) })
+
+	assert.Equal(t, 0, p.code)
+	assert.True(t, strings.HasPrefix(p.out.String(), info(meta)))
+	assert.Contains(t, p.out.String(), "Usage: <service> [Flags]\n")
+	assert.Contains(t, p.out.String(), "--log_level")
+
+	p.out.Reset()
+	pflag.Usage()
+	assert.True(t, strings.HasPrefix(p.out.String(), info(meta)))
+}
+
+func TestParseErrors(t *testing.T) {
+	p := useProcess(t, "--bogus")
+	p.run(func() { Parse(nil) })
+
+	assert.Equal(t, 2, p.code)
+	assert.Equal(t, "unknown flag: --bogus\n", p.err.String())
+}
+
+func TestParseE(t *testing.T) {
+	p := useProcess(t, "--help")
+	meta := &typepb.XMeta{ServiceName: "svc"}
+	require.ErrorIs(t, ParseE(meta), ErrHelp)
+	assert.Contains(t, p.out.String(), info(meta))
+
+	p = useProcess(t, "--bogus")
+	require.EqualError(t, ParseE(nil), "unknown flag: --bogus")
+	assert.Empty(t, p.out.String())
+	assert.Equal(t, -1, p.code)
+
+	useProcess(t, "--mode", "prod")
+	require.NoError(t, ParseE(nil))
+	assert.Equal(t, "prod", pflag.CommandLine.Lookup("mode").Value.String())
+}
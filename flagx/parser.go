@@ -0,0 +1,268 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	settingpb "github.com/sentinez/sentinez/api/gen/go/sentinez/setting/v1"
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/sentinez/shared/zlog"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrHelp is returned when -h or --help was passed. The usage has
+	// already been written.
+	ErrHelp = errors.New("help requested")
+
+	// ErrVersion is returned when --version was passed. The build info
+	// has already been written.
+	ErrVersion = errors.New("version requested")
+
+	// ErrCompletion is returned when the completion command ran. The
+	// script has already been written.
+	ErrCompletion = errors.New("completion requested")
+
+	// ErrParsed is returned by Execute when the process args were already
+	// parsed by Parse or Execute.
+	ErrParsed = errors.New("args already parsed")
+)
+
+// Parser parses service args on its own flag set. Unlike Parse, it
+// returns errors instead of exiting and can be used any number of times.
+type Parser struct {
+	meta  *typepb.XMeta
+	fs    *pflag.FlagSet
+	out   io.Writer
+	flags *settingpb.Flag
+
+	printConfig string
+	version     bool
+}
+
+// NewParser returns a parser with the global flags registered.
+func NewParser(meta *typepb.XMeta) *Parser {
+	fs := pflag.NewFlagSet(rootName, pflag.ContinueOnError)
+	return newParser(meta, fs, &settingpb.Flag{
+		EnvMode:  "dev",
+		LogLevel: "debug",
+	})
+}
+
+func newParser(meta *typepb.XMeta, fs *pflag.FlagSet,
+	flags *settingpb.Flag) *Parser {
+	p := &Parser{meta: meta, fs: fs, out: os.Stdout, flags: flags}
+	p.addGlobal()
+	return p
+}
+
+// addGlobal registers the flags shared by every command.
+func (p *Parser) addGlobal() {
+	p.fs.StringVarP(&p.flags.EnvMode, "mode", "m",
+		p.flags.GetEnvMode(), "run mode (dev|prod|sandbox)")
+	bindEnv(p.fs.Lookup("mode"), EnvName("mode"))
+	setChoices(p.fs.Lookup("mode"), "dev", "prod", "sandbox")
+
+	p.fs.Var(levelSpec{&p.flags.LogLevel}, "log_level",
+		"log level (debug|info|warn|error), or per scope, e.g. "+
+			"eventq=warn,*=info")
+	bindEnv(p.fs.Lookup("log_level"), EnvName("log_level"))
+	setChoices(p.fs.Lookup("log_level"), "debug", "info", "warn", "error")
+
+	p.fs.StringVar(&p.printConfig, "print-config", "",
+		"print the effective config and exit (text|json)")
+	p.fs.Lookup("print-config").NoOptDefVal = "text"
+	setChoices(p.fs.Lookup("print-config"), "text", "json")
+
+	p.fs.BoolVar(&p.version, "version", false, "print build info and exit")
+}
+
+// levelSpec is the value of --log_level, a zlog level spec checked when
+// set.
+type levelSpec struct {
+	spec *string
+}
+
+func (v levelSpec) Set(raw string) error {
+	if _, err := zlog.ParseSpec(raw); err != nil {
+		return err
+	}
+
+	*v.spec = raw
+	return nil
+}
+
+func (v levelSpec) String() string { return *v.spec }
+
+func (v levelSpec) Type() string { return "string" }
+
+// Register adds the fields of msg as global flags, see Register.
+func (p *Parser) Register(msg proto.Message) error {
+	return Register(p.fs, msg)
+}
+
+// FlagSet returns the global flags, e.g. to add custom ones.
+func (p *Parser) FlagSet() *pflag.FlagSet {
+	return p.fs
+}
+
+// SetOutput sets where usage and build info are written, os.Stdout by
+// default.
+func (p *Parser) SetOutput(w io.Writer) {
+	p.out = w
+}
+
+// Flags returns the parsed global flags.
+func (p *Parser) Flags() *settingpb.Flag {
+	return p.flags
+}
+
+// PrintConfig returns the format requested by --print-config, or an empty
+// string when the flag is not set.
+func (p *Parser) PrintConfig() string {
+	return p.printConfig
+}
+
+// BuildInfo describes the binary of the parser's service.
+func (p *Parser) BuildInfo() Build {
+	return buildInfo(p.meta)
+}
+
+// Parse parses args, without the program name. It returns ErrHelp or
+// ErrVersion when those were requested. Like Select, it handles the
+// hidden completion command, returning ErrCompletion once the script is
+// written.
+func (p *Parser) Parse(args []string) error {
+	root := &Command{fs: p.fs}
+	p.fs.Usage = func() {
+		root.printUsage(p.out, p.meta, rootName)
+	}
+
+	if err := p.parse(p.fs, args); err != nil {
+		return err
+	}
+
+	if rest := p.fs.Args(); len(rest) > 0 && rest[0] == completion {
+		if err := p.completionCommand(nil).Run(rest[1:]); err != nil {
+			return err
+		}
+
+		return ErrCompletion
+	}
+
+	return p.finish(p.fs)
+}
+
+// Execute selects a command from cmds by args and runs it.
+func (p *Parser) Execute(args []string, cmds ...*Command) error {
+	cmd, rest, err := p.Select(args, cmds...)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Run(rest)
+}
+
+// Select parses args and returns the command they select from cmds with
+// its positional args. Global flags are accepted before and after
+// command names. Flags not passed fall back to their env var, see Env.
+// A hidden completion command is added, see WriteCompletion.
+func (p *Parser) Select(args []string,
+	cmds ...*Command) (*Command, []string, error) {
+	if len(cmds) > 0 && (&Command{Commands: cmds}).find(completion) == nil {
+		cmds = append(slices.Clip(cmds), p.completionCommand(cmds))
+	}
+
+	root := &Command{Commands: cmds, fs: p.fs}
+	cmd, rest, err := p.selectCommand(root, rootName, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.finish(cmd.fs); err != nil {
+		return nil, nil, err
+	}
+
+	return cmd, rest, nil
+}
+
+func (p *Parser) selectCommand(c *Command, name string,
+	args []string) (*Command, []string, error) {
+	c.fs.SetInterspersed(len(c.Commands) == 0)
+	c.fs.Usage = func() {
+		c.printUsage(p.out, p.meta, name)
+	}
+
+	if err := p.parse(c.fs, args); err != nil {
+		return nil, nil, err
+	}
+
+	rest := c.fs.Args()
+	if len(c.Commands) == 0 || len(rest) == 0 {
+		if c.Run == nil && !p.version {
+			c.fs.Usage()
+			return nil, nil, fmt.Errorf("%s: missing command", name)
+		}
+
+		return c, rest, nil
+	}
+
+	sub := c.find(rest[0])
+	if sub == nil {
+		if c.Run != nil {
+			return c, rest, nil
+		}
+
+		c.fs.Usage()
+		return nil, nil, fmt.Errorf("unknown command %q", rest[0])
+	}
+
+	sub.fs = sub.newFlagSet(c.fs)
+	return p.selectCommand(sub, name+" "+sub.Name, rest[1:])
+}
+
+func (p *Parser) parse(fs *pflag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		return ErrHelp
+	}
+
+	return err
+}
+
+// finish applies the env var fallbacks once all args are parsed and
+// handles --version.
+func (p *Parser) finish(fs *pflag.FlagSet) error {
+	if err := applyEnv(fs); err != nil {
+		return err
+	}
+
+	if p.version {
+		_, err := fmt.Fprint(p.out, p.BuildInfo())
+		if err != nil {
+			return err
+		}
+
+		return ErrVersion
+	}
+
+	return nil
+}
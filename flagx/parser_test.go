@@ -0,0 +1,124 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"strings"
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// newTestParser returns a parser of the eventq service writing to out.
+func newTestParser(out *strings.Builder) *Parser {
+	p := NewParser(&typepb.XMeta{ServiceName: "eventq"})
+	p.SetOutput(out)
+	return p
+}
+
+func TestParser(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		env   map[string]string
+		mode  string
+		level string
+		print string
+	}{
+		{name: "defaults", mode: "dev", level: "debug"},
+		{name: "flags", args: []string{"-m", "prod", "--log_level", "warn"},
+			mode: "prod", level: "warn"},
+		{name: "env", args: []string{"--log_level", "warn"},
+			env:  map[string]string{"SENZ_MODE": "sandbox"},
+			mode: "sandbox", level: "warn"},
+		{name: "print config", args: []string{"--print-config"},
+			mode: "dev", level: "debug", print: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			p := newTestParser(&strings.Builder{})
+			require.NoError(t, p.Parse(tt.args))
+			assert.Equal(t, tt.mode, p.Flags().GetEnvMode())
+			assert.Equal(t, tt.level, p.Flags().GetLogLevel())
+			assert.Equal(t, tt.print, p.PrintConfig())
+		})
+	}
+}
+
+func TestParserExit(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		output  string
+	}{
+		{name: "help", args: []string{"--help"}, wantErr: ErrHelp,
+			output: "Usage: <service> [Flags]"},
+		{name: "version", args: []string{"--version"}, wantErr: ErrVersion,
+			output: "service: eventq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := newTestParser(&out).Parse(tt.args)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, out.String(), tt.output)
+		})
+	}
+}
+
+func TestParserInvalid(t *testing.T) {
+	p := NewParser(nil)
+	p.SetOutput(&strings.Builder{})
+	p.FlagSet().SetOutput(&strings.Builder{})
+
+	err := p.Parse([]string{"--unknown"})
+	assert.ErrorContains(t, err, "unknown flag: --unknown")
+	assert.NotErrorIs(t, err, ErrHelp)
+}
+
+func TestParserLogLevel(t *testing.T) {
+	p := NewParser(nil)
+	require.NoError(t, p.Parse([]string{"--log_level", "info,cron=debug"}))
+	assert.Equal(t, "info,cron=debug", p.Flags().GetLogLevel())
+
+	p = NewParser(nil)
+	p.FlagSet().SetOutput(&strings.Builder{})
+	err := p.Parse([]string{"--log_level", "info,cron=loud"})
+	assert.ErrorContains(t, err, `invalid level spec "cron=loud"`)
+
+	t.Setenv("SENZ_LOG_LEVEL", "=debug")
+	err = NewParser(nil).Parse(nil)
+	assert.ErrorContains(t, err, "SENZ_LOG_LEVEL")
+}
+
+func TestParserRegister(t *testing.T) {
+	msg := &descriptorpb.FileDescriptorProto{}
+	p := NewParser(nil)
+	require.NoError(t, p.Register(msg))
+
+	require.NoError(t, p.Parse([]string{"--name", "a.proto", "-m", "prod"}))
+	assert.Equal(t, "a.proto", msg.GetName())
+	assert.Equal(t, "prod", p.Flags().GetEnvMode())
+}
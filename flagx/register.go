@@ -0,0 +1,258 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	validatepb "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/sentinez/shared/protobuf"
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// listSep separates the elements of a repeated flag.
+const listSep = ","
+
+// Register adds a flag to fs for every field of msg and binds it to the
+// field, so parsing fs writes straight into msg. Flag names are the
+// lower-cased field names, nested messages joined with "_", matching the
+// names config.Flags reads. Usage comes from the field comment, or the
+// field name, followed by the enum values or protovalidate string.in
+// choices. Comments of messages compiled into Go code are only known
+// once their file is registered with protobuf.RegisterSourceInfo.
+// Defaults are the values already set in msg. Every flag falls back to
+// its EnvName env var. Maps and repeated messages are skipped. A nil fs
+// registers on pflag.CommandLine.
+func Register(fs *pflag.FlagSet, msg proto.Message) error {
+	if fs == nil {
+		fs = pflag.CommandLine
+	}
+
+	r := &registrar{fs: fs, root: msg.ProtoReflect()}
+	return r.register(r.root.Descriptor(), nil, "")
+}
+
+// registrar walks a message descriptor and registers its fields.
+type registrar struct {
+	fs   *pflag.FlagSet
+	root protoreflect.Message
+
+	// types holds the message types being walked, guarding against
+	// recursive messages.
+	types []protoreflect.FullName
+}
+
+func (r *registrar) register(md protoreflect.MessageDescriptor,
+	path []protoreflect.FieldDescriptor, prefix string) error {
+	if slices.Contains(r.types, md.FullName()) {
+		return nil
+	}
+
+	r.types = append(r.types, md.FullName())
+	defer func() { r.types = r.types[:len(r.types)-1] }()
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if err := r.field(fields.Get(i), path, prefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// field registers the flag of fd, or the flags of its fields when it is a
+// singular message.
+func (r *registrar) field(fd protoreflect.FieldDescriptor,
+	path []protoreflect.FieldDescriptor, prefix string) error {
+	name := strings.ToLower(string(fd.Name()))
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+
+	fdPath := append(slices.Clip(path), fd)
+	switch {
+	case fd.IsMap():
+		return nil
+	case fd.Kind() == protoreflect.MessageKind && !protobuf.IsDuration(fd):
+		if fd.IsList() {
+			return nil
+		}
+
+		return r.register(fd.Message(), fdPath, name)
+	}
+
+	if r.fs.Lookup(name) != nil {
+		return fmt.Errorf("flag redefined: %s", name)
+	}
+
+	f := r.fs.VarPF(&fieldValue{root: r.root, path: fdPath},
+		name, "", usage(fd))
+	if fd.Kind() == protoreflect.BoolKind && !fd.IsList() {
+		f.NoOptDefVal = "true"
+	}
+	bindEnv(f, EnvName(name))
+	setChoices(f, choices(fd)...)
+
+	return nil
+}
+
+// usage describes fd for the flag help.
+func usage(fd protoreflect.FieldDescriptor) string {
+	text := strings.Join(protobuf.Comments(fd), " ")
+	text = strings.TrimSuffix(text, ".")
+	if text == "" {
+		text = strings.ReplaceAll(string(fd.Name()), "_", " ")
+	}
+
+	if choices := choices(fd); len(choices) > 0 {
+		text += " (" + strings.Join(choices, "|") + ")"
+	}
+
+	return text
+}
+
+// choices returns the values fd accepts, when restricted.
+func choices(fd protoreflect.FieldDescriptor) []string {
+	if ed := fd.Enum(); ed != nil {
+		names := make([]string, 0, ed.Values().Len())
+		for i := 0; i < ed.Values().Len(); i++ {
+			names = append(names, string(ed.Values().Get(i).Name()))
+		}
+		return names
+	}
+
+	if fd.Options() == nil {
+		return nil
+	}
+
+	rules, _ := proto.GetExtension(fd.Options(),
+		validatepb.E_Field).(*validatepb.FieldRules)
+	return rules.GetString().GetIn()
+}
+
+// fieldValue is a pflag.Value backed by a field of root, reached through
+// path. Intermediate messages are only allocated when the flag is set.
+type fieldValue struct {
+	root protoreflect.Message
+	path []protoreflect.FieldDescriptor
+
+	// set is false until the first Set, which replaces the default
+	// elements of a repeated field instead of appending to them.
+	set bool
+}
+
+func (v *fieldValue) Set(raw string) error {
+	m := v.root
+	last := len(v.path) - 1
+	for _, fd := range v.path[:last] {
+		m = m.Mutable(fd).Message()
+	}
+
+	fd := v.path[last]
+	if !fd.IsList() {
+		val, err := protobuf.ParseValue(fd, raw)
+		if err != nil {
+			return err
+		}
+
+		m.Set(fd, val)
+		return nil
+	}
+
+	list := m.Mutable(fd).List()
+	if !v.set {
+		list.Truncate(0)
+		v.set = true
+	}
+
+	for _, part := range strings.Split(raw, listSep) {
+		val, err := protobuf.ParseValue(fd, strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		list.Append(val)
+	}
+
+	return nil
+}
+
+func (v *fieldValue) String() string {
+	m := v.root
+	last := len(v.path) - 1
+	for _, fd := range v.path[:last] {
+		if !m.Has(fd) {
+			return ""
+		}
+		m = m.Get(fd).Message()
+	}
+
+	fd := v.path[last]
+	if !fd.IsList() {
+		if !m.Has(fd) {
+			return ""
+		}
+		return formatValue(fd, m.Get(fd))
+	}
+
+	list := m.Get(fd).List()
+	parts := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		parts = append(parts, formatValue(fd, list.Get(i)))
+	}
+
+	return strings.Join(parts, listSep)
+}
+
+func (v *fieldValue) Type() string {
+	fd := v.path[len(v.path)-1]
+	name := fd.Kind().String()
+	switch {
+	case fd.Enum() != nil:
+		name = string(fd.Enum().Name())
+	case protobuf.IsDuration(fd):
+		name = "duration"
+	}
+
+	if fd.IsList() {
+		return name + "s"
+	}
+
+	return name
+}
+
+// formatValue renders a single value of fd the way ParseValue reads it.
+func formatValue(fd protoreflect.FieldDescriptor,
+	v protoreflect.Value) string {
+	switch val := v.Interface().(type) {
+	case protoreflect.EnumNumber:
+		if ev := fd.Enum().Values().ByNumber(val); ev != nil {
+			return string(ev.Name())
+		}
+	case protoreflect.Message:
+		if d, ok := val.Interface().(*durationpb.Duration); ok {
+			return d.AsDuration().String()
+		}
+	case []byte:
+		return string(val)
+	}
+
+	return v.String()
+}
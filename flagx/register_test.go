@@ -0,0 +1,121 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"testing"
+
+	"github.com/sentinez/shared/protobuf"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRegister(t *testing.T) {
+	msg := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("a.proto"),
+		Dependency: []string{"b.proto"},
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	require.NoError(t, Register(fs, msg))
+
+	assert.Equal(t, "a.proto", fs.Lookup("name").DefValue)
+	assert.Equal(t, "strings", fs.Lookup("dependency").Value.Type())
+	assert.Equal(t, "optimize for (SPEED|CODE_SIZE|LITE_RUNTIME)"+
+		" [$SENZ_OPTIONS_OPTIMIZE_FOR]",
+		fs.Lookup("options_optimize_for").Usage)
+	assert.Nil(t, fs.Lookup("message_type"))
+	assert.Nil(t, msg.Options, "nested messages allocated before Set")
+
+	require.NoError(t, fs.Parse([]string{
+		"--name", "c.proto",
+		"--dependency", "d.proto,e.proto",
+		"--dependency", "f.proto",
+		"--public_dependency", "1",
+		"--options_optimize_for", "CODE_SIZE",
+		"--options_cc_enable_arenas",
+	}))
+
+	assert.Equal(t, "c.proto", msg.GetName())
+	assert.Equal(t, []string{"d.proto", "e.proto", "f.proto"},
+		msg.GetDependency())
+	assert.Equal(t, []int32{1}, msg.GetPublicDependency())
+	assert.Equal(t, descriptorpb.FileOptions_CODE_SIZE,
+		msg.GetOptions().GetOptimizeFor())
+	assert.True(t, msg.GetOptions().GetCcEnableArenas())
+	assert.Equal(t, "CODE_SIZE",
+		fs.Lookup("options_optimize_for").Value.String())
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	require.NoError(t, Register(fs, &descriptorpb.FileDescriptorProto{}))
+
+	err := fs.Parse([]string{"--public_dependency", "x"})
+	assert.Error(t, err)
+}
+
+const commentedProto = `
+name: "flagx_test.proto"
+package: "flagx.test"
+syntax: "proto3"
+message_type {
+  name: "Server"
+  field { name: "address" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+}
+source_code_info {
+  location {
+    path: [4, 0, 2, 0]
+    span: [5, 2, 21]
+    leading_comments: " Address to listen on.\n"
+  }
+}
+`
+
+func TestRegisterComments(t *testing.T) {
+	fdp := &descriptorpb.FileDescriptorProto{}
+	require.NoError(t, prototext.Unmarshal([]byte(commentedProto), fdp))
+	require.NoError(t, protobuf.RegisterSourceInfo(
+		&descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{fdp},
+		}))
+
+	// Compiled descriptors carry no source info.
+	compiled := proto.Clone(fdp).(*descriptorpb.FileDescriptorProto)
+	compiled.SourceCodeInfo = nil
+	fd, err := protodesc.NewFile(compiled, nil)
+	require.NoError(t, err)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	msg := dynamicpb.NewMessage(fd.Messages().ByName("Server"))
+	require.NoError(t, Register(fs, msg))
+
+	assert.Equal(t, "Address to listen on [$SENZ_ADDRESS]",
+		fs.Lookup("address").Usage)
+}
+
+func TestRegisterRedefined(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.String("name", "", "")
+
+	err := Register(fs, &descriptorpb.FileDescriptorProto{})
+	assert.ErrorContains(t, err, "flag redefined: name")
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+)
+
+// version and commit are injected at build time, e.g.
+//
+//	go build -ldflags "-X github.com/sentinez/shared/flagx.version=v1.2.0
+//	  -X github.com/sentinez/shared/flagx.commit=$(git rev-parse HEAD)"
+//
+// When empty, the module version and VCS revision stamped by the Go
+// toolchain are used.
+var (
+	version string
+	commit  string
+)
+
+// Build describes the running binary.
+type Build struct {
+	Service   string `json:"service"`
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	Module    string `json:"module,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// BuildInfo returns the service meta passed to Parse or Execute, the
+// ldflags version and commit and the VCS and Go info of the running
+// binary.
+func BuildInfo() Build {
+	if std == nil {
+		return buildInfo(nil)
+	}
+
+	return std.BuildInfo()
+}
+
+func buildInfo(meta *typepb.XMeta) Build {
+	b := Build{
+		Service:   meta.GetServiceName(),
+		Key:       meta.GetServiceKey(),
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	b.Module = info.Main.Path
+	if b.Version == "" {
+		b.Version = info.Main.Version
+	}
+
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if b.Commit == "" {
+				b.Commit = s.Value
+			}
+		case "vcs.time":
+			b.Time = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+
+	return b
+}
+
+// String renders b as aligned key: value lines.
+func (b Build) String() string {
+	commit := b.Commit
+	if b.Modified {
+		commit += " (modified)"
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
+	for _, kv := range [][2]string{
+		{"service", b.Service},
+		{"key", b.Key},
+		{"version", b.Version},
+		{"commit", commit},
+		{"built", b.Time},
+		{"module", b.Module},
+		{"go", b.GoVersion + " " + b.Platform},
+	} {
+		if kv[1] != "" {
+			_, _ = fmt.Fprintf(w, "%s:\t%s\n", kv[0], kv[1])
+		}
+	}
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// VersionCommand returns a version command printing BuildInfo, the
+// subcommand form of --version.
+func VersionCommand() *Command {
+	return &Command{
+		Name:  "version",
+		Usage: "print build info",
+		Run: func([]string) error {
+			fmt.Print(BuildInfo())
+			return nil
+		},
+	}
+}
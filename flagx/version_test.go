@@ -0,0 +1,38 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flagx
+
+import (
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfo(t *testing.T) {
+	version, commit = "v1.2.0", "abc123"
+	t.Cleanup(func() { version, commit = "", "" })
+
+	b := buildInfo(&typepb.XMeta{ServiceName: "eventq", ServiceKey: "EVQ"})
+	assert.Equal(t, "eventq", b.Service)
+	assert.Equal(t, "EVQ", b.Key)
+	assert.Equal(t, "v1.2.0", b.Version)
+	assert.Equal(t, "abc123", b.Commit)
+	assert.NotEmpty(t, b.GoVersion)
+
+	out := b.String()
+	assert.Contains(t, out, "service: eventq\n")
+	assert.Contains(t, out, "version: v1.2.0\n")
+}
@@ -25,3 +25,9 @@ func Marshal(v any) ([]byte, error) {
 func Unmarshal(data []byte, v any) error {
 	return sonic.Unmarshal(data, v)
 }
+
+// MarshalIndent is Marshal with indentation, sorting map keys as
+// encoding/json does so that the output is stable.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	return sonic.ConfigStd.MarshalIndent(v, prefix, indent)
+}
@@ -20,6 +20,10 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -63,3 +67,95 @@ func TestValidate(t *testing.T) {
 		t.Errorf("Validate(%v) = %v, want nil", msg, err)
 	}
 }
+
+const commentedProto = `
+name: "protobuf_test.proto"
+package: "protobuf.test"
+syntax: "proto3"
+message_type {
+  name: "Server"
+  field { name: "address" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+}
+source_code_info {
+  location {
+    path: [4, 0, 2, 0]
+    span: [5, 2, 21]
+    leading_comments: " Address to listen on.\n   Defaults to :8080.\n"
+  }
+}
+`
+
+// useSourceFiles gives the test an empty RegisterSourceInfo registry.
+func useSourceFiles(t *testing.T) {
+	prev := sourceInfo
+	sourceInfo = newSourceFiles()
+	t.Cleanup(func() { sourceInfo = prev })
+}
+
+// commentedFile returns the descriptor of commentedProto.
+func commentedFile(t *testing.T) *descriptorpb.FileDescriptorProto {
+	fdp := &descriptorpb.FileDescriptorProto{}
+	if err := prototext.Unmarshal([]byte(commentedProto), fdp); err != nil {
+		t.Fatal(err)
+	}
+	return fdp
+}
+
+func TestComments(t *testing.T) {
+	useSourceFiles(t)
+	fdp := commentedFile(t)
+
+	compiled := proto.Clone(fdp).(*descriptorpb.FileDescriptorProto)
+	compiled.SourceCodeInfo = nil
+	fd, err := protodesc.NewFile(compiled, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := fd.Messages().ByName("Server").Fields().ByName("address")
+	if got := Comments(field); got != nil {
+		t.Errorf("Comments() = %q before registration, want nil", got)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{fdp},
+	}
+	if err := RegisterSourceInfo(set); err != nil {
+		t.Fatalf("RegisterSourceInfo() = %v", err)
+	}
+
+	want := []string{"Address to listen on.", "Defaults to :8080."}
+	if diff := cmp.Diff(want, Comments(field)); diff != "" {
+		t.Errorf("Comments() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRegisterSourceInfo(t *testing.T) {
+	useSourceFiles(t)
+	fdp := commentedFile(t)
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{fdp},
+	}
+	if err := RegisterSourceInfo(set); err != nil {
+		t.Fatalf("RegisterSourceInfo() = %v", err)
+	}
+	if err := RegisterSourceInfo(set); err != nil {
+		t.Errorf("RegisterSourceInfo() of the same file = %v, want nil", err)
+	}
+
+	other := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("protobuf_other.proto"),
+		Package: proto.String("protobuf.other"),
+	}
+	changed := proto.Clone(fdp).(*descriptorpb.FileDescriptorProto)
+	changed.SourceCodeInfo = nil
+	set.File = []*descriptorpb.FileDescriptorProto{other, changed}
+	if err := RegisterSourceInfo(set); err == nil {
+		t.Error("RegisterSourceInfo() of a changed file = nil, want error")
+	}
+
+	if _, err := sourceInfo.files.FindFileByPath(other.GetName()); err == nil {
+		t.Error("file of a failed RegisterSourceInfo() registered")
+	}
+}
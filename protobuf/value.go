@@ -0,0 +1,207 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var durationName = (&durationpb.Duration{}).ProtoReflect().
+	Descriptor().FullName()
+
+// IsDuration reports whether fd holds google.protobuf.Duration values.
+func IsDuration(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.MessageKind &&
+		fd.Message().FullName() == durationName
+}
+
+// ParseValue parses raw as a single value of fd. Enums accept a value
+// name or number and durations use time.ParseDuration syntax. Other
+// message kinds are not supported.
+// nolint:funlen
+func ParseValue(fd protoreflect.FieldDescriptor,
+	raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(raw)), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(raw, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.EnumKind:
+		return parseEnum(fd.Enum(), raw)
+	case protoreflect.MessageKind:
+		if IsDuration(fd) {
+			return parseDuration(raw)
+		}
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("unsupported field type %s",
+		fd.Kind())
+}
+
+func parseEnum(ed protoreflect.EnumDescriptor,
+	raw string) (protoreflect.Value, error) {
+	if ev := ed.Values().ByName(protoreflect.Name(raw)); ev != nil {
+		return protoreflect.ValueOfEnum(ev.Number()), nil
+	}
+
+	n, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return protoreflect.Value{}, fmt.Errorf("unknown %s value %q",
+			ed.Name(), raw)
+	}
+
+	return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+}
+
+func parseDuration(raw string) (protoreflect.Value, error) {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	return protoreflect.ValueOfMessage(durationpb.New(d).ProtoReflect()), nil
+}
+
+// sourceInfo holds the files registered with RegisterSourceInfo.
+var sourceInfo = newSourceFiles()
+
+// sourceFiles is a registry of files with source info, along with the
+// descriptors they were built from.
+type sourceFiles struct {
+	sync.RWMutex
+	files  *protoregistry.Files
+	protos map[string]*descriptorpb.FileDescriptorProto
+}
+
+func newSourceFiles() *sourceFiles {
+	return &sourceFiles{
+		files:  &protoregistry.Files{},
+		protos: map[string]*descriptorpb.FileDescriptorProto{},
+	}
+}
+
+// RegisterSourceInfo makes the comments of the files in set available to
+// Comments. Descriptors compiled into Go code carry no source info, so
+// their comments are looked up by name in set, which is built with it,
+// e.g. by buf build or protoc --include_source_info
+// --descriptor_set_out. Files already registered with the same contents,
+// such as shared imports, are skipped. The set is registered as a whole
+// or, on error, not at all.
+func RegisterSourceInfo(set *descriptorpb.FileDescriptorSet) error {
+	sourceInfo.Lock()
+	defer sourceInfo.Unlock()
+
+	files := &protoregistry.Files{}
+	sourceInfo.files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		_ = files.RegisterFile(fd)
+		return true
+	})
+	protos := maps.Clone(sourceInfo.protos)
+
+	opts := protodesc.FileOptions{AllowUnresolvable: true}
+	for _, fdp := range set.GetFile() {
+		if prev, ok := protos[fdp.GetName()]; ok {
+			if !proto.Equal(prev, fdp) {
+				return fmt.Errorf("file %q is already registered with "+
+					"other contents", fdp.GetName())
+			}
+			continue
+		}
+
+		fd, err := opts.New(fdp, files)
+		if err != nil {
+			return err
+		}
+
+		if err := files.RegisterFile(fd); err != nil {
+			return err
+		}
+		clone := proto.Clone(fdp).(*descriptorpb.FileDescriptorProto)
+		protos[fdp.GetName()] = clone
+	}
+
+	sourceInfo.files, sourceInfo.protos = files, protos
+	return nil
+}
+
+// Comments returns the leading comment lines of d, from its own source
+// info or else from the files registered with RegisterSourceInfo.
+func Comments(d protoreflect.Descriptor) []string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	if loc.LeadingComments == "" {
+		loc = registeredLocation(d)
+	}
+
+	text := strings.TrimSpace(loc.LeadingComments)
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return lines
+}
+
+// registeredLocation returns the source location of the descriptor
+// registered under the name of d, if any.
+func registeredLocation(d protoreflect.Descriptor) protoreflect.SourceLocation {
+	sourceInfo.RLock()
+	defer sourceInfo.RUnlock()
+
+	rd, err := sourceInfo.files.FindDescriptorByName(d.FullName())
+	if err != nil {
+		return protoreflect.SourceLocation{}
+	}
+
+	return rd.ParentFile().SourceLocations().ByDescriptor(rd)
+}
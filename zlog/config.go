@@ -16,13 +16,11 @@ package zlog
 
 import (
 	"os"
-	"sync"
 	"time"
 
+	"github.com/sentinez/shared/color"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 var (
@@ -37,9 +35,6 @@ var (
 		EncodeLevel:   zapcore.CapitalColorLevelEncoder,
 		EncodeCaller:  zapcore.ShortCallerEncoder,
 	}
-
-	onceSetScope sync.Once
-	onceSetLevel sync.Once
 )
 
 const (
@@ -85,24 +80,49 @@ func (l Level) Int() int {
 	return int(l)
 }
 
-// configConsoleLogger creates a new logger.
-func configConsoleLogger(scope string) *zap.Logger {
-	var consoleConf = config
+// Sink is a destination of log lines, such as Stdout or a FileSink.
+type Sink = zapcore.WriteSyncer
 
-	// Console output
-	consoleEncoder := zapcore.NewConsoleEncoder(consoleConf)
+// Stdout is the sink of loggers created without sinks.
+var Stdout Sink = zapcore.Lock(os.Stdout)
 
-	core := zapcore.NewCore(consoleEncoder,
-		zapcore.Lock(os.Stdout), zapcore.DebugLevel)
+// configConsoleLogger creates a new logger writing to sinks, or Stdout
+// without any. Only Stdout gets colors.
+func configConsoleLogger(scope string, level zapcore.LevelEnabler,
+	sinks ...Sink) *zap.Logger {
+	var consoleConf = config
+	consoleConf.EncodeName = colorNameEncoder
+
+	var plainConf = config
+	plainConf.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	var cores []zapcore.Core
+	var files []Sink
+	for _, sink := range withStdout(sinks) {
+		if sink == Stdout {
+			// Console output
+			cores = append(cores, zapcore.NewCore(
+				zapcore.NewConsoleEncoder(consoleConf), sink, level))
+		} else {
+			files = append(files, sink)
+		}
+	}
 
-	logger := zap.New(core, zap.AddCaller(),
-		zap.AddCallerSkip(2), zap.AddStacktrace(zapcore.FatalLevel))
+	if len(files) > 0 {
+		cores = append(cores, zapcore.NewCore(
+			zapcore.NewConsoleEncoder(plainConf),
+			zapcore.NewMultiWriteSyncer(files...), level))
+	}
+
+	logger := zap.New(zapcore.NewTee(cores...), zap.AddCaller(),
+		zap.AddCallerSkip(1), zap.AddStacktrace(zapcore.FatalLevel))
 
 	logger = logger.Named(scope)
 	return logger
 }
 
-func configJSONLogger(scope string) *zap.Logger {
+func configJSONLogger(scope string, level zapcore.LevelEnabler,
+	sinks ...Sink) *zap.Logger {
 	var jsonConf = config
 	jsonConf.EncodeTime = zap.NewProductionEncoderConfig().EncodeTime
 	jsonConf.EncodeLevel = zapcore.CapitalLevelEncoder
@@ -110,7 +130,7 @@ func configJSONLogger(scope string) *zap.Logger {
 	jsonEncoder := zapcore.NewJSONEncoder(jsonConf)
 
 	core := zapcore.NewCore(jsonEncoder,
-		zapcore.Lock(os.Stdout), zapcore.DebugLevel)
+		zapcore.NewMultiWriteSyncer(withStdout(sinks)...), level)
 
 	logger := zap.New(core)
 
@@ -118,23 +138,66 @@ func configJSONLogger(scope string) *zap.Logger {
 	return logger
 }
 
-func timeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-	enc.AppendString(t.Format("2006-01-02T15:04:05.000Z07:00"))
+// withStdout returns sinks, or Stdout when there are none.
+func withStdout(sinks []Sink) []Sink {
+	if len(sinks) == 0 {
+		return []Sink{Stdout}
+	}
+
+	return sinks
+}
+
+// colorNameEncoder colors logger names on the console.
+func colorNameEncoder(name string, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(color.Green.Add(name))
+}
+
+// levelCore gates a core on its own level, so a child logger can be more
+// verbose than its parent. Entries it accepts are written to the wrapped
+// core directly, skipping the level of that core.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// withLevel returns log gated on level instead of its current level.
+func withLevel(log *zap.Logger, level zapcore.LevelEnabler) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+
+		return &levelCore{Core: core, level: level}
+	}))
+}
+
+// Enabled implements zapcore.Core.
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
 }
 
-// SetLogLevel set logger default level
-func SetLogLevel(ll Level) {
-	onceSetLevel.Do(func() {
-		consoleLevel = ll
-		console = NewDefaultConsole(consoleLevel)
-	})
+// Level implements zapcore.LevelEnabler's optional Level method.
+func (c *levelCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.level)
 }
 
-func SetScopeLogLevel(scope string, ll Level) {
-	onceSetScope.Do(func() {
-		consoleLevel = ll
-		console = NewConsole(scope, consoleLevel)
-	})
+// With implements zapcore.Core.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check implements zapcore.Core.
+func (c *levelCore) Check(ent zapcore.Entry,
+	ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func timeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(t.Format("2006-01-02T15:04:05.000Z07:00"))
 }
 
 func ToLevel(logLevel string) Level {
@@ -156,117 +219,3 @@ func ToLevel(logLevel string) Level {
 
 	return level
 }
-
-func marshaler(event proto.Message) zapcore.ObjectMarshaler {
-	return zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
-		return marshalProto(enc, event)
-	})
-}
-
-func marshalProto(enc zapcore.ObjectEncoder, msg proto.Message) error {
-	m := msg.ProtoReflect()
-	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
-		name := string(fd.Name())
-
-		switch {
-		case fd.IsList():
-			// repeated field -> log slice
-			arr := make([]any, 0, v.List().Len())
-			for i := 0; i < v.List().Len(); i++ {
-				arr = append(arr, valueOf(fd, v.List().Get(i)))
-			}
-			_ = enc.AddReflected(name, arr)
-
-		case fd.IsMap():
-			// map field -> log map
-			tmp := make(map[string]any)
-			v.Map().Range(
-				func(k protoreflect.MapKey, v protoreflect.Value) bool {
-					tmp[k.String()] = valueOf(fd.MapValue(), v)
-					return true
-				})
-			_ = enc.AddReflected(name, tmp)
-
-		default:
-			// scalar field
-			addScalar(enc, name, fd, v)
-		}
-		return true
-	})
-	return nil
-}
-
-// nolint:funlen
-func addScalar(enc zapcore.ObjectEncoder, key string,
-	fd protoreflect.FieldDescriptor, v protoreflect.Value) {
-	switch fd.Kind() {
-	case protoreflect.StringKind:
-		enc.AddString(key, v.String())
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
-		protoreflect.Sfixed32Kind:
-		enc.AddInt32(key, int32(v.Int()))
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
-		protoreflect.Sfixed64Kind:
-		enc.AddInt64(key, v.Int())
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		enc.AddUint32(key, uint32(v.Uint()))
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		enc.AddUint64(key, v.Uint())
-	case protoreflect.FloatKind:
-		enc.AddFloat32(key, float32(v.Float()))
-	case protoreflect.DoubleKind:
-		enc.AddFloat64(key, v.Float())
-	case protoreflect.BoolKind:
-		enc.AddBool(key, v.Bool())
-	case protoreflect.BytesKind:
-		enc.AddBinary(key, v.Bytes())
-	case protoreflect.EnumKind:
-		enc.AddInt32(key, int32(v.Enum()))
-	case protoreflect.MessageKind:
-		_ = enc.AddObject(key, zapcore.ObjectMarshalerFunc(
-			func(inner zapcore.ObjectEncoder) error {
-				return marshalProto(inner, v.Message().Interface())
-			}))
-	default:
-		_ = enc.AddReflected(key, v.Interface())
-	}
-}
-
-// nolint:funlen
-func valueOf(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
-	switch fd.Kind() {
-	case protoreflect.StringKind:
-		return v.String()
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
-		protoreflect.Sfixed32Kind:
-		return int32(v.Int())
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
-		protoreflect.Sfixed64Kind:
-		return v.Int()
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		return uint32(v.Uint())
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return v.Uint()
-	case protoreflect.FloatKind:
-		return float32(v.Float())
-	case protoreflect.DoubleKind:
-		return v.Float()
-	case protoreflect.BoolKind:
-		return v.Bool()
-	case protoreflect.BytesKind:
-		return v.Bytes()
-	case protoreflect.EnumKind:
-		return int32(v.Enum())
-	case protoreflect.MessageKind:
-
-		m := make(map[string]any)
-		v.Message().Range(
-			func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
-				m[string(fd.Name())] = valueOf(fd, val)
-				return true
-			})
-		return m
-	default:
-		return nil
-	}
-}
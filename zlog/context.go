@@ -0,0 +1,77 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"context"
+	"slices"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"go.uber.org/zap"
+)
+
+// Keys of the fields usually carried by a request context.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	TenantKey    = "tenant"
+	ConsoleKey   = "console"
+)
+
+// fieldsKey is the context key of the log fields.
+type fieldsKey struct{}
+
+// RequestID returns the request ID field.
+func RequestID(id string) zap.Field {
+	return zap.String(RequestIDKey, id)
+}
+
+// TraceID returns the trace ID field.
+func TraceID(id string) zap.Field {
+	return zap.String(TraceIDKey, id)
+}
+
+// Tenant returns the tenant field.
+func Tenant(tenant string) zap.Field {
+	return zap.String(TenantKey, tenant)
+}
+
+// Console returns the console field, named after the enum value.
+func Console(c typepb.Console) zap.Field {
+	return zap.String(ConsoleKey, c.String())
+}
+
+// WithContext returns a copy of ctx carrying fields after the ones ctx
+// already carries. Loggers obtained with FromContext or the Context
+// method add them to every line.
+func WithContext(ctx context.Context, fields ...zap.Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, fieldsKey{},
+		slices.Concat(Fields(ctx), fields))
+}
+
+// Fields returns the fields carried by ctx.
+func Fields(ctx context.Context) []zap.Field {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// FromContext returns the package console with the fields of ctx.
+func FromContext(ctx context.Context) Sugar {
+	return console.Context(ctx)
+}
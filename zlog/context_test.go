@@ -0,0 +1,107 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"context"
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, ctx, WithContext(ctx))
+	assert.Empty(t, Fields(ctx))
+
+	parent := WithContext(ctx, RequestID("r1"), Tenant("acme"))
+	child := WithContext(parent, TraceID("t1"))
+	sibling := WithContext(parent, TraceID("t2"))
+
+	assert.Equal(t, []zap.Field{RequestID("r1"), Tenant("acme")},
+		Fields(parent))
+	assert.Equal(t, []zap.Field{RequestID("r1"), Tenant("acme"),
+		TraceID("t1")}, Fields(child))
+	assert.Equal(t, TraceID("t2"), Fields(sibling)[2])
+}
+
+func TestContextSugar(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	atomic := zap.NewAtomicLevelAt(zap.DebugLevel)
+	c := createSugard(zap.New(core).Sugar(), atomic)
+
+	ctx := WithContext(context.Background(), RequestID("r1"), TraceID("t1"))
+	c.Context(ctx).Infof("handled %s", "req")
+	c.Info("no fields")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "handled req", entries[0].Message)
+	assert.Equal(t, map[string]any{
+		RequestIDKey: "r1",
+		TraceIDKey:   "t1",
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].Context)
+
+	atomic.SetLevel(zap.WarnLevel)
+	c.Context(ctx).Info("dropped")
+	assert.Equal(t, 2, logs.Len())
+}
+
+func TestContextLogger(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := createLogger(zap.New(core), typepb.LogKind(0),
+		zap.NewAtomicLevelAt(zap.DebugLevel))
+
+	ctx := WithContext(context.Background(), Tenant("acme"))
+	l.Context(ctx).Warn("quota", wrapperspb.String("exceeded"))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "acme", entries[0].ContextMap()[TenantKey])
+	assert.Equal(t, map[string]any{"value": "exceeded"},
+		entries[0].ContextMap()[loggerEvent])
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := WithContext(context.Background(), RequestID("r1"))
+	assert.Equal(t, console.Level(), FromContext(ctx).Level())
+	FromContext(ctx).Debug("This is a debug message with a request ID")
+}
+
+func TestContextConsole(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	atomic := zap.NewAtomicLevelAt(zap.DebugLevel)
+	c := createSugard(zap.New(core).Sugar(), atomic)
+
+	ctx := WithContext(context.Background(), Tenant("acme"),
+		Console(typepb.Console_CONSOLE_ADMIN))
+	assert.Equal(t, Console(typepb.Console_CONSOLE_ADMIN), Fields(ctx)[1])
+	c.Context(ctx).Info("signed in")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		TenantKey:  "acme",
+		ConsoleKey: "CONSOLE_ADMIN",
+	}, entries[0].ContextMap())
+
+	FromContext(ctx).Debug("This is a debug message with a console")
+}
@@ -14,81 +14,90 @@
 
 package zlog
 
-var (
-	consoleLevel = LevelDebug
-	console      = NewDefaultConsole(LevelDebug)
-)
+// console is the package console. The functions below call its zap logger
+// directly, so that it reports their caller like any other console.
+var console = newConsole("", level)
+
+// consoleSinks are the sinks of the package console, Stdout when empty.
+var consoleSinks []Sink
+
+// SetSinks makes the package console write to sinks, e.g. Stdout and a
+// FileSink. Call it at startup, like SetScopeLogLevel.
+func SetSinks(sinks ...Sink) {
+	consoleSinks = sinks
+	console = newConsole(console.scope, level, sinks...)
+}
 
 // Info logs an info message.
 func Info(message ...any) {
-	if v(LevelInfo) {
-		console.Info(message...)
+	if v(LevelInfo) && console.sampleArgs(LevelInfo, message) {
+		console.Logger.Info(message...)
 	}
 }
 
 // Infof logs an info message with a format.
 func Infof(template string, message ...any) {
-	if v(LevelInfo) {
-		console.Infof(template, message...)
+	if v(LevelInfo) && console.sample(LevelInfo, template) {
+		console.Logger.Infof(template, message...)
 	}
 }
 
 // Debug logs a debug message.
 func Debug(message ...any) {
-	if v(LevelDebug) {
-		console.Debug(message...)
+	if v(LevelDebug) && console.sampleArgs(LevelDebug, message) {
+		console.Logger.Debug(message...)
 	}
 }
 
 // Debugf logs a debug message.
 func Debugf(template string, message ...any) {
-	if v(LevelDebug) {
-		console.Debugf(template, message...)
+	if v(LevelDebug) && console.sample(LevelDebug, template) {
+		console.Logger.Debugf(template, message...)
 	}
 }
 
 // Error logs an error message.
 func Error(message ...any) {
-	if v(LevelError) {
-		console.Error(message...)
+	if v(LevelError) && console.sampleArgs(LevelError, message) {
+		console.Logger.Error(message...)
 	}
 }
 
 // Errorf logs an error message with a format.
 func Errorf(template string, message ...any) {
-	if v(LevelError) {
-		console.Errorf(template, message...)
+	if v(LevelError) && console.sample(LevelError, template) {
+		console.Logger.Errorf(template, message...)
 	}
 }
 
 // Warn logs an warn message.
 func Warn(message ...any) {
-	if v(LevelWarning) {
-		console.Warning(message...)
+	if v(LevelWarning) && console.sampleArgs(LevelWarning, message) {
+		console.Logger.Warn(message...)
 	}
 }
 
 // Warnf logs an error message with a format.
 func Warnf(template string, message ...any) {
-	if v(LevelWarning) {
-		console.Warningf(template, message...)
+	if v(LevelWarning) && console.sample(LevelWarning, template) {
+		console.Logger.Warnf(template, message...)
 	}
 }
 
 // Fatal logs a fatal message.
 func Fatal(message ...any) {
 	if v(LevelFatal) {
-		console.Fatal(message...)
+		console.Logger.Fatal(message...)
 	}
 }
 
 // Fatalf logs a fatal message.
 func Fatalf(template string, message ...any) {
 	if v(LevelFatal) {
-		console.Fatalf(template, message...)
+		console.Logger.Fatalf(template, message...)
 	}
 }
 
 func v(ll Level) bool {
-	return ll >= consoleLevel
+	return level.Enabled(ll.Zap())
 }
@@ -0,0 +1,435 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTime is the layout of the time in rotated file names.
+const backupTime = "2006-01-02T15-04-05.000"
+
+// compressSuffix is the suffix of compressed rotated files.
+const compressSuffix = ".gz"
+
+// openFiles holds the file sinks with an open file, see ReopenFiles.
+var openFiles = struct {
+	sync.Mutex
+	sinks map[*FileSink]struct{}
+}{sinks: map[*FileSink]struct{}{}}
+
+// FileSink is a Sink writing to a file, which it rotates by size and age.
+// Rotated files are renamed after their rotation time, e.g.
+// app-2025-01-02T15-04-05.000.log, with a sequence number such as
+// app-2025-01-02T15-04-05.000-1.log for rotations within the same
+// millisecond, optionally gzipped, and removed past the retention limits.
+// The file is opened on the first write and is reopened by ReopenFiles,
+// e.g. on SIGHUP with WatchReopen, so that it can also be rotated by
+// logrotate.
+//
+// A FileSink must not be copied after first use. Loggers writing to the
+// same path must share one FileSink.
+type FileSink struct {
+	// Path is the file written to.
+	Path string
+
+	// MaxSize rotates the file before it grows past MaxSize bytes.
+	MaxSize int64
+
+	// MaxAge rotates the file once it has been open for MaxAge.
+	MaxAge time.Duration
+
+	// MaxBackups is the number of rotated files kept, all when zero.
+	MaxBackups int
+
+	// Retention removes the rotated files older than Retention.
+	Retention time.Duration
+
+	// Compress gzips the rotated files.
+	Compress bool
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	// mill serializes the compression and removal of rotated files.
+	mill sync.Mutex
+	wg   sync.WaitGroup
+
+	// now returns the current time, time.Now when nil.
+	now func() time.Time
+}
+
+// Write implements Sink, rotating the file first when needed.
+func (s *FileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if s.due(int64(len(p))) {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// Sync implements Sink.
+func (s *FileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	return s.file.Sync()
+}
+
+// Rotate rotates the file now.
+func (s *FileSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	return s.rotate()
+}
+
+// Reopen closes and reopens the file, e.g. after it was moved away. A
+// closed sink is left closed until the next write.
+func (s *FileSink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+// Close closes the file and waits for pending compressions. The file is
+// opened again on the next write.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	openFiles.Lock()
+	delete(openFiles.sinks, s)
+	openFiles.Unlock()
+
+	s.wg.Wait()
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *FileSink) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+
+	return time.Now()
+}
+
+// open opens the file for appending and registers s for ReopenFiles.
+func (s *FileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	s.file, s.size, s.opened = f, info.Size(), s.clock()
+
+	openFiles.Lock()
+	openFiles.sinks[s] = struct{}{}
+	openFiles.Unlock()
+
+	return nil
+}
+
+// due tells whether the file must be rotated before writing n bytes.
+func (s *FileSink) due(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+
+	if s.MaxSize > 0 && s.size+n > s.MaxSize {
+		return true
+	}
+
+	return s.MaxAge > 0 && s.clock().Sub(s.opened) >= s.MaxAge
+}
+
+// rotate renames the file after the current time, opens a new one and
+// cleans up the rotated files in the background.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	if err := os.Rename(s.Path, s.backupName(s.clock())); err != nil {
+		return err
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.cleanup()
+	}()
+
+	return nil
+}
+
+// backupName returns the name of the file rotated at t, numbered after
+// the rotated files of the same time, compressed or not.
+func (s *FileSink) backupName(t time.Time) string {
+	ext := filepath.Ext(s.Path)
+	base := strings.TrimSuffix(s.Path, ext) + "-" + t.UTC().Format(backupTime)
+
+	name := base + ext
+	for seq := 1; exists(name) || exists(name+compressSuffix); seq++ {
+		name = base + "-" + strconv.Itoa(seq) + ext
+	}
+	return name
+}
+
+// exists tells whether a file exists at path.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// backup is a rotated file.
+type backup struct {
+	path string
+	time time.Time
+	seq  int
+}
+
+// backups returns the rotated files of s, newest first.
+func (s *FileSink) backups() ([]backup, error) {
+	dir := filepath.Dir(s.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(s.Path)
+	prefix := strings.TrimSuffix(filepath.Base(s.Path), ext) + "-"
+
+	var out []backup
+	for _, e := range entries {
+		b, ok := parseBackup(e.Name(), prefix, ext)
+		if !ok || e.IsDir() {
+			continue
+		}
+		b.path = filepath.Join(dir, e.Name())
+		out = append(out, b)
+	}
+
+	slices.SortFunc(out, func(a, b backup) int {
+		if c := b.time.Compare(a.time); c != 0 {
+			return c
+		}
+		return b.seq - a.seq
+	})
+	return out, nil
+}
+
+// parseBackup returns the rotation time and sequence number of a rotated
+// file name, e.g. app-2025-01-02T15-04-05.000-1.log.gz for the prefix
+// app- and the extension .log.
+func parseBackup(name, prefix, ext string) (backup, bool) {
+	stamp, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return backup{}, false
+	}
+
+	stamp = strings.TrimSuffix(stamp, compressSuffix)
+	stamp, ok = strings.CutSuffix(stamp, ext)
+	if !ok || len(stamp) < len(backupTime) {
+		return backup{}, false
+	}
+
+	t, err := time.Parse(backupTime, stamp[:len(backupTime)])
+	if err != nil {
+		return backup{}, false
+	}
+
+	rest := stamp[len(backupTime):]
+	if rest == "" {
+		return backup{time: t}, true
+	}
+
+	digits, ok := strings.CutPrefix(rest, "-")
+	seq, err := strconv.Atoi(digits)
+	if !ok || err != nil || seq < 1 {
+		return backup{}, false
+	}
+	return backup{time: t, seq: seq}, true
+}
+
+// cleanup removes the rotated files past MaxBackups or Retention and
+// compresses the others when Compress is set.
+func (s *FileSink) cleanup() {
+	s.mill.Lock()
+	defer s.mill.Unlock()
+
+	backups, err := s.backups()
+	if err != nil {
+		return
+	}
+
+	cutoff := s.clock().Add(-s.Retention)
+	for i, b := range backups {
+		switch {
+		case s.MaxBackups > 0 && i >= s.MaxBackups,
+			s.Retention > 0 && b.time.Before(cutoff):
+			_ = os.Remove(b.path)
+		case s.Compress && !strings.HasSuffix(b.path, compressSuffix):
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// compressFile gzips path into path.gz and removes path.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.Create(path + compressSuffix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dst.Name())
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+
+	if err = errors.Join(zw.Close(), dst.Close()); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// ReopenFiles reopens the files of all file sinks, e.g. after logrotate
+// moved them away. See WatchReopen.
+func ReopenFiles() error {
+	openFiles.Lock()
+	sinks := make([]*FileSink, 0, len(openFiles.sinks))
+	for s := range openFiles.sinks {
+		sinks = append(sinks, s)
+	}
+	openFiles.Unlock()
+
+	var errs []error
+	for _, s := range sinks {
+		errs = append(errs, s.Reopen())
+	}
+
+	return errors.Join(errs...)
+}
+
+// WatchReopen calls ReopenFiles on every SIGHUP until stop is called.
+// Call it at startup when the log files are rotated by logrotate.
+func WatchReopen() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := ReopenFiles(); err != nil {
+					Errorf("reopen log files: err=%v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
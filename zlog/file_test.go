@@ -0,0 +1,248 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// fakeClock is a clock advanced by the test.
+type fakeClock struct{ nanos atomic.Int64 }
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{}
+	c.nanos.Store(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).UnixNano())
+	return c
+}
+
+func (c *fakeClock) now() time.Time {
+	return time.Unix(0, c.nanos.Load()).UTC()
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.nanos.Add(int64(d))
+}
+
+// newFileSink returns a sink in a temp dir closed after the test.
+func newFileSink(t *testing.T, clock *fakeClock) *FileSink {
+	s := &FileSink{Path: filepath.Join(t.TempDir(), "logs", "app.log")}
+	if clock != nil {
+		s.now = clock.now
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// dirNames returns the names of the files next to s.Path.
+func dirNames(t *testing.T, s *FileSink) []string {
+	s.wg.Wait()
+	entries, err := os.ReadDir(filepath.Dir(s.Path))
+	require.NoError(t, err)
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestFileSinkMaxSize(t *testing.T) {
+	clock := newFakeClock()
+	s := newFileSink(t, clock)
+	s.MaxSize = 10
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err := s.Write([]byte(line))
+		require.NoError(t, err)
+		clock.advance(time.Second)
+	}
+
+	assert.Equal(t, []string{
+		"app-2025-01-02T03-04-06.000.log",
+		"app-2025-01-02T03-04-07.000.log",
+		"app.log",
+	}, dirNames(t, s))
+
+	data, err := os.ReadFile(s.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
+
+func TestFileSinkMaxAge(t *testing.T) {
+	clock := newFakeClock()
+	s := newFileSink(t, clock)
+	s.MaxAge = time.Hour
+	s.MaxBackups = 2
+	s.Compress = true
+
+	for range 4 {
+		_, err := s.Write([]byte("line\n"))
+		require.NoError(t, err)
+		clock.advance(30 * time.Minute)
+		_, err = s.Write([]byte("line\n"))
+		require.NoError(t, err)
+		clock.advance(30 * time.Minute)
+		s.wg.Wait()
+	}
+	_, err := s.Write([]byte("last\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"app-2025-01-02T06-04-05.000.log.gz",
+		"app-2025-01-02T07-04-05.000.log.gz",
+		"app.log",
+	}, dirNames(t, s))
+
+	f, err := os.Open(filepath.Join(filepath.Dir(s.Path),
+		"app-2025-01-02T07-04-05.000.log.gz"))
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "line\nline\n", string(data))
+}
+
+func TestFileSinkRetention(t *testing.T) {
+	clock := newFakeClock()
+	s := newFileSink(t, clock)
+	s.Retention = 36 * time.Hour
+
+	for range 3 {
+		_, err := s.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, s.Rotate())
+		s.wg.Wait()
+		clock.advance(24 * time.Hour)
+	}
+
+	assert.Equal(t, []string{
+		"app-2025-01-03T03-04-05.000.log",
+		"app-2025-01-04T03-04-05.000.log",
+		"app.log",
+	}, dirNames(t, s))
+}
+
+func TestFileSinkSameTime(t *testing.T) {
+	s := newFileSink(t, newFakeClock())
+	s.MaxBackups = 2
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err := s.Write([]byte(line))
+		require.NoError(t, err)
+		require.NoError(t, s.Rotate())
+		s.wg.Wait()
+	}
+
+	assert.Equal(t, []string{
+		"app-2025-01-02T03-04-05.000-1.log",
+		"app-2025-01-02T03-04-05.000-2.log",
+		"app.log",
+	}, dirNames(t, s))
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(s.Path),
+		"app-2025-01-02T03-04-05.000-2.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
+
+func TestWatchReopen(t *testing.T) {
+	s := newFileSink(t, nil)
+	_, err := s.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	stop := WatchReopen()
+	defer stop()
+
+	require.NoError(t, os.Rename(s.Path, s.Path+".1"))
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(s.Path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	stop()
+}
+
+func TestFileSinkReopen(t *testing.T) {
+	s := newFileSink(t, nil)
+	_, err := s.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	moved := s.Path + ".1"
+	require.NoError(t, os.Rename(s.Path, moved))
+	require.NoError(t, ReopenFiles())
+
+	_, err = s.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "before\n", string(data))
+
+	data, err = os.ReadFile(s.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(data))
+
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Reopen())
+	assert.Nil(t, s.file)
+}
+
+func TestLoggerSinks(t *testing.T) {
+	restoreScopes(t)
+	s := newFileSink(t, nil)
+
+	c := NewConsole("filetest", LevelInfo, s)
+	c.Named("child").Infof("written to %s", "file")
+	require.NoError(t, c.Sync())
+
+	l := NewJSONLogger("jsontest", typepb.LogKind(0), LevelInfo, s)
+	l.Warn("json line", wrapperspb.String("v"))
+	require.NoError(t, l.Sync())
+
+	data, err := os.ReadFile(s.Path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	assert.NotContains(t, lines[0], "\x1b[")
+	assert.Contains(t, lines[0], "INFO\tfiletest.child\t")
+	assert.Contains(t, lines[0], "written to file")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "jsontest", entry["name"])
+	assert.Equal(t, map[string]any{"value": "v"}, entry["event"])
+}
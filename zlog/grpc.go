@@ -0,0 +1,124 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"context"
+
+	"github.com/sentinez/shared/errorx"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// LevelServiceName is the name of the gRPC service reading and setting
+// the log levels as a level spec, see SetLevelSpec.
+const LevelServiceName = "sentinez.zlog.Level"
+
+const (
+	getLevelMethod = "/" + LevelServiceName + "/Get"
+	setLevelMethod = "/" + LevelServiceName + "/Set"
+)
+
+// levelServer is the interface of the level service handlers.
+type levelServer interface {
+	Get(ctx context.Context,
+		req *emptypb.Empty) (*wrapperspb.StringValue, error)
+	Set(ctx context.Context,
+		req *wrapperspb.StringValue) (*wrapperspb.StringValue, error)
+}
+
+var levelServiceDesc = grpc.ServiceDesc{
+	ServiceName: LevelServiceName,
+	HandlerType: (*levelServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: getLevelHandler},
+		{MethodName: "Set", Handler: setLevelHandler},
+	},
+}
+
+// RegisterLevelService registers the level service on s. Get returns the
+// LevelSpec and Set applies a level or spec and returns the new one.
+func RegisterLevelService(s grpc.ServiceRegistrar) {
+	s.RegisterService(&levelServiceDesc, levelService{})
+}
+
+// GetLevel calls the level service Get method on cc.
+func GetLevel(ctx context.Context, cc grpc.ClientConnInterface) (string,
+	error) {
+	out := &wrapperspb.StringValue{}
+	err := cc.Invoke(ctx, getLevelMethod, &emptypb.Empty{}, out)
+	return out.GetValue(), err
+}
+
+// SetLevel calls the level service Set method on cc.
+func SetLevel(ctx context.Context, cc grpc.ClientConnInterface,
+	name string) (string, error) {
+	out := &wrapperspb.StringValue{}
+	err := cc.Invoke(ctx, setLevelMethod, wrapperspb.String(name), out)
+	return out.GetValue(), err
+}
+
+type levelService struct{}
+
+func (levelService) Get(context.Context,
+	*emptypb.Empty) (*wrapperspb.StringValue, error) {
+	return wrapperspb.String(LevelSpec()), nil
+}
+
+func (levelService) Set(_ context.Context,
+	req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	if err := SetLevelSpec(req.GetValue()); err != nil {
+		return nil, errorx.StatusInvalidArgumentF("%v", err)
+	}
+
+	return wrapperspb.String(LevelSpec()), nil
+}
+
+func getLevelHandler(srv any, ctx context.Context, dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := &emptypb.Empty{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(levelServer).Get(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: getLevelMethod}
+	return interceptor(ctx, in, info, func(ctx context.Context,
+		req any) (any, error) {
+		return srv.(levelServer).Get(ctx, req.(*emptypb.Empty))
+	})
+}
+
+func setLevelHandler(srv any, ctx context.Context, dec func(any) error,
+	interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := &wrapperspb.StringValue{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(levelServer).Set(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: setLevelMethod}
+	return interceptor(ctx, in, info, func(ctx context.Context,
+		req any) (any, error) {
+		return srv.(levelServer).Set(ctx, req.(*wrapperspb.StringValue))
+	})
+}
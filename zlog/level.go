@@ -0,0 +1,173 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// level is the level of the package console. It can be changed at any
+// time, e.g. through LevelHandler or the gRPC level service.
+var level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+
+// Zap returns the zap level of l.
+func (l Level) Zap() zapcore.Level {
+	switch {
+	case l <= LevelDebug:
+		return zapcore.DebugLevel
+	case l == LevelInfo:
+		return zapcore.InfoLevel
+	case l == LevelWarning:
+		return zapcore.WarnLevel
+	case l == LevelError:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.FatalLevel
+	}
+}
+
+// fromZap returns the Level of a zap level.
+func fromZap(l zapcore.Level) Level {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return LevelDebug
+	case l == zapcore.InfoLevel:
+		return LevelInfo
+	case l == zapcore.WarnLevel:
+		return LevelWarning
+	case l == zapcore.ErrorLevel:
+		return LevelError
+	default:
+		return LevelFatal
+	}
+}
+
+// ParseLevel parses a level name as accepted by ToLevel, rejecting
+// unknown names instead of falling back to debug.
+func ParseLevel(name string) (Level, error) {
+	switch name {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	default:
+		return LevelDebug, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// SetLogLevel sets the level of the package console. It can be called
+// any number of times.
+func SetLogLevel(ll Level) {
+	level.SetLevel(ll.Zap())
+}
+
+// GetLogLevel returns the level of the package console.
+func GetLogLevel() Level {
+	return fromZap(level.Level())
+}
+
+// SetScopeLogLevel names the package console after scope and sets its
+// level, unless the applied level spec has a rule for scope, which then
+// wins. Call it at startup; the level can be changed later with
+// SetLogLevel.
+func SetScopeLogLevel(scope string, ll Level) {
+	console = newConsole(scope, level, consoleSinks...)
+	if rule, ok := scopes.current().rule(scope); ok {
+		ll = rule
+	}
+
+	SetLogLevel(ll)
+}
+
+// AtomicLevel returns the level of the package console.
+func AtomicLevel() zap.AtomicLevel {
+	return level
+}
+
+// LevelHandler serves the level of the package console over HTTP. GET
+// returns it as {"level":"info"} and PUT applies a level or level spec,
+// e.g. eventq=warn,*=info, from a JSON body of the same shape or a level
+// form value, through SetLevelSpec like the gRPC level service. A scope
+// query parameter selects the level of a named logger instead, which PUT
+// sets until the next SetLevelSpec.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := r.URL.Query().Get("scope")
+		switch {
+		case scope == "" && r.Method == http.MethodPut:
+			putLevelSpec(w, r)
+			return
+		case scope == "":
+			level.ServeHTTP(w, r)
+			return
+		}
+
+		l, ok := scopes.lookup(scope)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown scope %q", scope),
+				http.StatusNotFound)
+			return
+		}
+
+		l.ServeHTTP(w, r)
+	})
+}
+
+// putLevelSpec applies the level spec of r and replies as zap's level
+// handler does.
+func putLevelSpec(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Level *string `json:"level"`
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	var err error
+	if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		spec := r.FormValue("level")
+		req.Level = &spec
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	switch {
+	case err != nil:
+	case req.Level == nil:
+		err = errors.New("must specify logging level")
+	default:
+		err = SetLevelSpec(*req.Level)
+	}
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = enc.Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = enc.Encode(map[string]string{"level": level.Level().String()})
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// restoreLevel resets the package console level after the test.
+func restoreLevel(t *testing.T) {
+	prev := GetLogLevel()
+	t.Cleanup(func() { SetLogLevel(prev) })
+}
+
+func TestSetLogLevel(t *testing.T) {
+	restoreLevel(t)
+
+	SetLogLevel(LevelError)
+	assert.Equal(t, LevelError, GetLogLevel())
+	assert.False(t, v(LevelWarning))
+	assert.True(t, v(LevelError))
+
+	SetLogLevel(LevelInfo)
+	assert.Equal(t, LevelInfo, GetLogLevel())
+	assert.True(t, v(LevelWarning))
+	assert.False(t, v(LevelDebug))
+
+	c := NewConsole("test", LevelWarning)
+	assert.False(t, c.V(LevelInfo.Int()))
+	c.Level().SetLevel(LevelDebug.Zap())
+	assert.True(t, c.V(LevelDebug.Int()))
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{
+		"debug":   LevelDebug,
+		"info":    LevelInfo,
+		"warn":    LevelWarning,
+		"warning": LevelWarning,
+		"error":   LevelError,
+		"fatal":   LevelFatal,
+	} {
+		got, err := ParseLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, fromZap(got.Zap()))
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLevelHandler(t *testing.T) {
+	restoreLevel(t)
+	SetLogLevel(LevelInfo)
+
+	rec := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/",
+		strings.NewReader(`{"level":"error"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, LevelError, GetLogLevel())
+}
+
+func TestLevelHandlerSpec(t *testing.T) {
+	restoreScopes(t)
+	SetLogLevel(LevelInfo)
+	eventq := NewConsole("eventq", LevelDebug)
+
+	rec := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/",
+		strings.NewReader(`{"level":"eventq=warn,error"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"error"}`, rec.Body.String())
+	assert.Equal(t, LevelError, GetLogLevel())
+	assert.False(t, eventq.V(LevelInfo.Int()))
+	assert.True(t, eventq.V(LevelWarning.Int()))
+	assert.Equal(t, "error,eventq=warning", LevelSpec())
+
+	req := httptest.NewRequest(http.MethodPut, "/",
+		strings.NewReader("level=debug"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "debug", LevelSpec())
+
+	rec = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/",
+		strings.NewReader(`{"level":"loud"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, LevelDebug, GetLogLevel())
+}
+
+func TestLevelService(t *testing.T) {
+	restoreLevel(t)
+	SetLogLevel(LevelInfo)
+
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer()
+	RegisterLevelService(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context,
+			_ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	ctx := context.Background()
+	got, err := GetLevel(ctx, cc)
+	require.NoError(t, err)
+	assert.Equal(t, "info", got)
+
+	got, err = SetLevel(ctx, cc, "warn")
+	require.NoError(t, err)
+	assert.Equal(t, "warning", got)
+	assert.Equal(t, LevelWarning, GetLogLevel())
+
+	_, err = SetLevel(ctx, cc, "loud")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -15,6 +15,8 @@
 package zlog
 
 import (
+	"context"
+
 	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
@@ -33,28 +35,48 @@ type Logger interface {
 	Warn(msg string, event proto.Message)
 	Error(msg string, event proto.Message)
 	V(l int) bool
+	Level() zap.AtomicLevel
+	Context(ctx context.Context) Logger
+	With(fields ...zap.Field) Logger
+	Named(name string) Logger
 	Sync() error
 }
 
-func NewJSONLogger(named string, logKind typepb.LogKind, level Level) Logger {
-	logger := configJSONLogger(named)
-	return createLogger(logger, logKind, ToLevel(level.String()).Int())
+// NewJSONLogger creates a JSON logger named after its scope, writing to
+// sinks or Stdout without any. Its level is shared with the other loggers
+// of the scope, see NewConsole.
+func NewJSONLogger(named string, logKind typepb.LogKind, level Level,
+	sinks ...Sink) Logger {
+	atomic := scopes.level(named, level)
+	log := configJSONLogger(named, atomic, sinks...)
+	return &logger{
+		scope: named,
+		log:   log,
+		base:  log,
+		kind:  logKind,
+		level: atomic,
+	}
 }
 
 func createLogger(log *zap.Logger,
-	kind typepb.LogKind, verbosity int) Logger {
-	return &logger{log: log, verbosity: verbosity, kind: kind}
+	kind typepb.LogKind, level zap.AtomicLevel) Logger {
+	return &logger{log: log, base: log, level: level, kind: kind}
 }
 
 type logger struct {
-	log       *zap.Logger
-	kind      typepb.LogKind
-	verbosity int
+	scope string
+	log   *zap.Logger
+	kind  typepb.LogKind
+	level zap.AtomicLevel
+
+	// base is log without the fields of With and Context, see
+	// sugard.base.
+	base *zap.Logger
 }
 
 // Debug implements Logger.
 func (l *logger) Debug(msg string, event proto.Message) {
-	if l.V(LevelDebug.Int()) {
+	if l.V(LevelDebug.Int()) && l.sample(LevelDebug, msg) {
 		l.log.Debug(msg,
 			zap.Object(loggerEvent, marshaler(event)))
 	}
@@ -62,7 +84,7 @@ func (l *logger) Debug(msg string, event proto.Message) {
 
 // Error implements Logger.
 func (l *logger) Error(msg string, event proto.Message) {
-	if l.V(LevelError.Int()) {
+	if l.V(LevelError.Int()) && l.sample(LevelError, msg) {
 		l.log.Error(msg,
 			zap.Object(loggerEvent, marshaler(event)))
 	}
@@ -70,7 +92,7 @@ func (l *logger) Error(msg string, event proto.Message) {
 
 // Info implements Logger.
 func (l *logger) Info(msg string, event proto.Message) {
-	if l.V(LevelInfo.Int()) {
+	if l.V(LevelInfo.Int()) && l.sample(LevelInfo, msg) {
 		l.log.Info(msg, zap.String(loggerKind, l.kind.String()),
 			zap.Object(loggerEvent, marshaler(event)))
 	}
@@ -81,14 +103,68 @@ func (l *logger) Sync() error {
 	return l.log.Sync()
 }
 
+// sample tells whether a line at ll with msg passes sampling, see
+// SetSampling.
+func (l *logger) sample(ll Level, msg string) bool {
+	return sampled(l.scope, ll, msg, l.base)
+}
+
 // V implements Logger.
 func (l *logger) V(ll int) bool {
-	return ll >= l.verbosity
+	return l.level.Enabled(Level(ll).Zap())
+}
+
+// Level implements Logger.
+func (l *logger) Level() zap.AtomicLevel {
+	return l.level
+}
+
+// Context implements Logger.
+func (l *logger) Context(ctx context.Context) Logger {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.with(l.log.With(fields...))
+}
+
+// With implements Logger.
+func (l *logger) With(fields ...zap.Field) Logger {
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.with(l.log.With(fields...))
+}
+
+// Named implements Logger. The child has its own level, like the ones of
+// Sugar.Named.
+func (l *logger) Named(name string) Logger {
+	scope := childScope(l.scope, name)
+	level := scopes.level(scope, fromZap(l.level.Level()))
+	return &logger{
+		scope: scope,
+		log:   withLevel(l.log.Named(name), level),
+		base:  withLevel(l.base.Named(name), level),
+		kind:  l.kind,
+		level: level,
+	}
+}
+
+func (l *logger) with(log *zap.Logger) *logger {
+	return &logger{
+		scope: l.scope,
+		log:   log,
+		base:  l.base,
+		kind:  l.kind,
+		level: l.level,
+	}
 }
 
 // Warn implements Logger.
 func (l *logger) Warn(msg string, event proto.Message) {
-	if l.V(LevelWarning.Int()) {
+	if l.V(LevelWarning.Int()) && l.sample(LevelWarning, msg) {
 		l.log.Warn(msg,
 			zap.Object(loggerEvent, marshaler(event)))
 	}
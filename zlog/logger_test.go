@@ -0,0 +1,56 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"testing"
+
+	typepb "github.com/sentinez/sentinez/api/gen/go/sentinez/types/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLoggerChildren(t *testing.T) {
+	restoreScopes(t)
+	require.NoError(t, SetLevelSpec("audit.export=debug"))
+
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core).Named("audit")
+	l := &logger{
+		scope: "audit",
+		log:   log,
+		base:  log,
+		kind:  typepb.LogKind(0),
+		level: scopes.level("audit", LevelInfo),
+	}
+
+	export := l.With(zap.String("component", "audit")).Named("export")
+	export.Debug("row", wrapperspb.String("r1"))
+	l.Debug("dropped", wrapperspb.String("r2"))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "audit.export", entries[0].LoggerName)
+	assert.Equal(t, "audit", entries[0].ContextMap()["component"])
+	assert.True(t, export.V(LevelDebug.Int()))
+	assert.False(t, l.V(LevelDebug.Int()))
+
+	assert.Same(t, l, l.With())
+	assert.Equal(t, l.Level(), l.With(zap.Int("n", 1)).Level())
+	assert.Equal(t, zap.InfoLevel, l.Named("import").Level().Level())
+}
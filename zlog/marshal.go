@@ -0,0 +1,344 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// anyName is the full name of google.protobuf.Any.
+const anyName = "google.protobuf.Any"
+
+// jsonTypes are the well-known types rendered through protojson.
+var jsonTypes = map[protoreflect.FullName]bool{
+	"google.protobuf.Timestamp": true,
+	"google.protobuf.Duration":  true,
+	"google.protobuf.Struct":    true,
+	"google.protobuf.Value":     true,
+	"google.protobuf.ListValue": true,
+	"google.protobuf.FieldMask": true,
+	"google.protobuf.Empty":     true,
+}
+
+// wrapperTypes are the well-known types wrapping a single value field.
+var wrapperTypes = map[protoreflect.FullName]bool{
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+// marshaler logs event like protojson renders it: fields by their JSON
+// name, 64-bit integers as strings, enums by name, well-known types in
+// their JSON form and Any unpacked with its @type. Unlike protojson, the
+// oneof of a set field is labeled with the JSON name of that field.
+func marshaler(event proto.Message) zapcore.ObjectMarshaler {
+	return zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		return marshalProto(enc, event)
+	})
+}
+
+func marshalProto(enc zapcore.ObjectEncoder, msg proto.Message) error {
+	marshalObject(enc, msg.ProtoReflect(), redaction.Load(), "")
+	return nil
+}
+
+// messageMarshaler logs a message at path from the event as an object.
+type messageMarshaler struct {
+	m    protoreflect.Message
+	r    *redactor
+	path string
+}
+
+func (mm messageMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	marshalObject(enc, mm.m, mm.r, mm.path)
+	return nil
+}
+
+// listMarshaler logs the elements of the list field fd.
+type listMarshaler struct {
+	fd   protoreflect.FieldDescriptor
+	list protoreflect.List
+	r    *redactor
+	path string
+}
+
+func (lm listMarshaler) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	for i := 0; i < lm.list.Len(); i++ {
+		appendScalar(arr, lm.fd, lm.list.Get(i), lm.r, lm.path)
+	}
+	return nil
+}
+
+// mapMarshaler logs the entries of the map field fd, sorted by key.
+type mapMarshaler struct {
+	fd   protoreflect.FieldDescriptor
+	m    protoreflect.Map
+	r    *redactor
+	path string
+}
+
+func (mm mapMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]protoreflect.MapKey, 0, mm.m.Len())
+	mm.m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	slices.SortFunc(keys, func(a, b protoreflect.MapKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, k := range keys {
+		addScalar(enc, k.String(), mm.fd.MapValue(), mm.m.Get(k),
+			mm.r, mm.path)
+	}
+	return nil
+}
+
+// marshalObject adds m to enc. Any adds @type and the unpacked message,
+// and the other well-known types their JSON form as value.
+func marshalObject(enc zapcore.ObjectEncoder, m protoreflect.Message,
+	r *redactor, path string) {
+	if m.Descriptor().FullName() == anyName {
+		url, inner, ok := unpackAny(m)
+		enc.AddString("@type", url)
+		if !ok {
+			return
+		}
+		m = inner
+	}
+
+	if v, ok := wellKnown(m); ok {
+		_ = enc.AddReflected("value", v)
+		return
+	}
+
+	marshalMessage(enc, m, r, path)
+}
+
+// marshalMessage adds the fields of m, at path from the event, to enc,
+// redacting the sensitive ones.
+func marshalMessage(enc zapcore.ObjectEncoder, m protoreflect.Message,
+	r *redactor, path string) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := fd.JSONName()
+		path := fieldPath(path, string(fd.Name()))
+
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			enc.AddString(string(od.Name()), name)
+		}
+
+		switch {
+		case r.match(fd, path):
+			if fd.IsList() || fd.IsMap() {
+				_ = enc.AddReflected(name, r.field(fd, v))
+			} else {
+				enc.AddString(name, r.value(v))
+			}
+
+		case fd.IsList():
+			// repeated field -> log array
+			_ = enc.AddArray(name, listMarshaler{fd, v.List(), r, path})
+
+		case fd.IsMap():
+			// map field -> log object
+			_ = enc.AddObject(name, mapMarshaler{fd, v.Map(), r, path})
+
+		default:
+			// scalar field
+			addScalar(enc, name, fd, v, r, path)
+		}
+		return true
+	})
+}
+
+// fieldPath returns the path of the field name of the message at path.
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// nolint:funlen
+func addScalar(enc zapcore.ObjectEncoder, key string,
+	fd protoreflect.FieldDescriptor, v protoreflect.Value,
+	r *redactor, path string) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		enc.AddString(key, v.String())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind:
+		enc.AddInt32(key, int32(v.Int()))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed64Kind:
+		enc.AddString(key, strconv.FormatInt(v.Int(), 10))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		enc.AddUint32(key, uint32(v.Uint()))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		enc.AddString(key, strconv.FormatUint(v.Uint(), 10))
+	case protoreflect.FloatKind:
+		enc.AddFloat32(key, float32(v.Float()))
+	case protoreflect.DoubleKind:
+		enc.AddFloat64(key, v.Float())
+	case protoreflect.BoolKind:
+		enc.AddBool(key, v.Bool())
+	case protoreflect.BytesKind:
+		enc.AddBinary(key, v.Bytes())
+	case protoreflect.EnumKind:
+		_ = enc.AddReflected(key, enumValue(fd, v))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if wk, ok := wellKnown(v.Message()); ok {
+			_ = enc.AddReflected(key, wk)
+			return
+		}
+		_ = enc.AddObject(key, messageMarshaler{v.Message(), r, path})
+	default:
+		_ = enc.AddReflected(key, v.Interface())
+	}
+}
+
+// nolint:funlen
+func appendScalar(arr zapcore.ArrayEncoder, fd protoreflect.FieldDescriptor,
+	v protoreflect.Value, r *redactor, path string) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if wk, ok := wellKnown(v.Message()); ok {
+			_ = arr.AppendReflected(wk)
+			return
+		}
+		_ = arr.AppendObject(messageMarshaler{v.Message(), r, path})
+	case protoreflect.StringKind:
+		arr.AppendString(v.String())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind:
+		arr.AppendInt32(int32(v.Int()))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind,
+		protoreflect.Sfixed64Kind:
+		arr.AppendString(strconv.FormatInt(v.Int(), 10))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		arr.AppendUint32(uint32(v.Uint()))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		arr.AppendString(strconv.FormatUint(v.Uint(), 10))
+	case protoreflect.FloatKind:
+		arr.AppendFloat32(float32(v.Float()))
+	case protoreflect.DoubleKind:
+		arr.AppendFloat64(v.Float())
+	case protoreflect.BoolKind:
+		arr.AppendBool(v.Bool())
+	case protoreflect.BytesKind:
+		_ = arr.AppendReflected(v.Bytes())
+	case protoreflect.EnumKind:
+		_ = arr.AppendReflected(enumValue(fd, v))
+	default:
+		_ = arr.AppendReflected(v.Interface())
+	}
+}
+
+// enumValue returns the name of an enum value, nil for NullValue, or the
+// number of values missing from the descriptor.
+func enumValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	ed := fd.Enum()
+	if ed.FullName() == "google.protobuf.NullValue" {
+		return nil
+	}
+
+	if ev := ed.Values().ByNumber(v.Enum()); ev != nil {
+		return string(ev.Name())
+	}
+
+	return int32(v.Enum())
+}
+
+// wellKnown returns the JSON form of the well-known type m, the value of
+// wrappers. Any is not covered, it is rendered by marshalObject.
+func wellKnown(m protoreflect.Message) (any, bool) {
+	name := m.Descriptor().FullName()
+	switch {
+	case wrapperTypes[name]:
+		fd := m.Descriptor().Fields().ByName("value")
+		return scalarValue(fd, m.Get(fd)), true
+
+	case jsonTypes[name]:
+		b, err := protojson.Marshal(m.Interface())
+		if err != nil {
+			return nil, false
+		}
+
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, false
+		}
+		return v, true
+
+	default:
+		return nil, false
+	}
+}
+
+// scalarValue returns the Go value of a wrapper field, 64-bit integers
+// as strings.
+func scalarValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind:
+		return int32(v.Int())
+	case protoreflect.Int64Kind:
+		return strconv.FormatInt(v.Int(), 10)
+	case protoreflect.Uint32Kind:
+		return uint32(v.Uint())
+	case protoreflect.Uint64Kind:
+		return strconv.FormatUint(v.Uint(), 10)
+	case protoreflect.FloatKind:
+		return float32(v.Float())
+	default:
+		return v.Interface()
+	}
+}
+
+// unpackAny returns the type URL of the Any m and the message it holds,
+// when its type is registered. Unregistered payloads are not logged, as
+// their fields cannot be redacted.
+func unpackAny(m protoreflect.Message) (string, protoreflect.Message, bool) {
+	fields := m.Descriptor().Fields()
+	url := m.Get(fields.ByNumber(1)).String()
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(url)
+	if err != nil {
+		return url, nil, false
+	}
+
+	inner := mt.New()
+	err = proto.Unmarshal(m.Get(fields.ByNumber(2)).Bytes(), inner.Interface())
+	if err != nil {
+		return url, nil, false
+	}
+
+	return url, inner, true
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const eventProto = `
+name: "zlog_event_test.proto"
+package: "zlog.test"
+syntax: "proto3"
+dependency: "google/protobuf/any.proto"
+dependency: "google/protobuf/duration.proto"
+dependency: "google/protobuf/struct.proto"
+dependency: "google/protobuf/timestamp.proto"
+dependency: "google/protobuf/wrappers.proto"
+enum_type {
+  name: "State"
+  value { name: "STATE_UNSPECIFIED" number: 0 }
+  value { name: "STATE_READY" number: 1 }
+}
+message_type {
+  name: "Event"
+  field {
+    name: "state" number: 1 type: TYPE_ENUM label: LABEL_OPTIONAL
+    type_name: ".zlog.test.State"
+  }
+  field {
+    name: "states" number: 2 type: TYPE_ENUM label: LABEL_REPEATED
+    type_name: ".zlog.test.State"
+  }
+  field {
+    name: "at" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".google.protobuf.Timestamp"
+  }
+  field {
+    name: "took" number: 4 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".google.protobuf.Duration"
+  }
+  field {
+    name: "attrs" number: 5 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".google.protobuf.Struct"
+  }
+  field {
+    name: "count" number: 6 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".google.protobuf.Int64Value"
+  }
+  field {
+    name: "detail" number: 7 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".google.protobuf.Any"
+  }
+  field {
+    name: "email" number: 8 type: TYPE_STRING label: LABEL_OPTIONAL
+    oneof_index: 0
+  }
+  field {
+    name: "phone" number: 9 type: TYPE_STRING label: LABEL_OPTIONAL
+    oneof_index: 0
+  }
+  field {
+    name: "history" number: 10 type: TYPE_MESSAGE label: LABEL_REPEATED
+    type_name: ".google.protobuf.Timestamp"
+  }
+  field {
+    name: "children" number: 11 type: TYPE_MESSAGE label: LABEL_REPEATED
+    type_name: ".zlog.test.Event"
+  }
+  field {
+    name: "unknown" number: 12 type: TYPE_ENUM label: LABEL_OPTIONAL
+    type_name: ".zlog.test.State"
+  }
+  field {
+    name: "total_bytes" number: 13 type: TYPE_INT64 label: LABEL_OPTIONAL
+  }
+  field {
+    name: "seq_ids" number: 14 type: TYPE_UINT64 label: LABEL_REPEATED
+  }
+  oneof_decl { name: "contact" }
+}
+`
+
+const eventText = `
+state: STATE_READY
+states: [STATE_UNSPECIFIED, STATE_READY]
+at { seconds: 1700000000 nanos: 500000000 }
+took { seconds: 90 }
+attrs {
+  fields { key: "ok" value { bool_value: true } }
+  fields { key: "tags" value { list_value { values { string_value: "a" } } } }
+}
+count { value: 42 }
+detail {
+  [type.googleapis.com/google.protobuf.Duration] { seconds: 1 }
+}
+phone: "555"
+history { seconds: 0 }
+children { state: STATE_READY email: "kid@example.com" }
+unknown: 7
+total_bytes: 9007199254740993
+seq_ids: [1, 18446744073709551615]
+`
+
+func TestMarshalProto(t *testing.T) {
+	fdp := &descriptorpb.FileDescriptorProto{}
+	require.NoError(t, prototext.Unmarshal([]byte(eventProto), fdp))
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(fd.Messages().ByName("Event"))
+	require.NoError(t, prototext.Unmarshal([]byte(eventText), msg))
+
+	assert.Equal(t, map[string]any{
+		"state":  "STATE_READY",
+		"states": []any{"STATE_UNSPECIFIED", "STATE_READY"},
+		"at":     "2023-11-14T22:13:20.500Z",
+		"took":   "90s",
+		"attrs": map[string]any{
+			"ok":   true,
+			"tags": []any{"a"},
+		},
+		"count": "42",
+		"detail": map[string]any{
+			"@type": "type.googleapis.com/google.protobuf.Duration",
+			"value": "1s",
+		},
+		"contact": "phone",
+		"phone":   "555",
+		"history": []any{"1970-01-01T00:00:00Z"},
+		"children": []any{map[string]any{
+			"state":   "STATE_READY",
+			"contact": "email",
+			"email":   "kid@example.com",
+		}},
+		"unknown":    int32(7),
+		"totalBytes": "9007199254740993",
+		"seqIds":     []any{"1", "18446744073709551615"},
+	}, marshalFields(t, msg))
+}
+
+func TestMarshalProtoAny(t *testing.T) {
+	restoreRedaction(t)
+	SetRedaction(RedactMask, nil, "seconds")
+
+	detail, err := anypb.New(timestamppb.New(time.Unix(0, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"@type": "type.googleapis.com/google.protobuf.Timestamp",
+		"value": "1970-01-01T00:00:00Z",
+	}, marshalFields(t, detail))
+
+	assert.Equal(t, map[string]any{"value": "2s"},
+		marshalFields(t, durationpb.New(2*time.Second)))
+
+	detail.TypeUrl = "type.googleapis.com/zlog.test.Missing"
+	assert.Equal(t, map[string]any{"@type": detail.TypeUrl},
+		marshalFields(t, detail))
+}
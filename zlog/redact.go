@@ -0,0 +1,155 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Redaction is how the Logger event marshaler hides sensitive fields.
+type Redaction int
+
+const (
+	// RedactMask replaces sensitive values with RedactedMask.
+	RedactMask Redaction = iota
+
+	// RedactHash replaces sensitive values with a short HMAC-SHA256 of
+	// the redaction key, so that equal values can still be correlated
+	// across lines without being guessable from their hash. Values are
+	// masked when no key is set.
+	RedactHash
+)
+
+// RedactedMask is the value logged in place of masked fields.
+const RedactedMask = "[REDACTED]"
+
+// redaction holds the redactor of the event marshaler. Fields with the
+// debug_redact option are masked by default.
+var redaction atomic.Pointer[redactor]
+
+func init() {
+	redaction.Store(&redactor{})
+}
+
+// SetRedaction sets how sensitive event fields are logged and which
+// fields are sensitive besides the ones with the debug_redact option.
+// A field is either a name, e.g. password, matching fields of that name
+// in any message, or a dotted path of field names from the event, e.g.
+// user.email. Both use the proto names of the fields, not the JSON names
+// they are logged with. Paths go through lists and maps of messages.
+// Well-known types, such as Struct, match as a whole rather than by
+// their inner fields. key is the HMAC key of RedactHash, unused by
+// RedactMask. Each call replaces the key and fields of the previous one.
+func SetRedaction(mode Redaction, key []byte, fields ...string) {
+	r := &redactor{mode: mode, key: slices.Clone(key)}
+	for _, f := range fields {
+		if strings.Contains(f, ".") {
+			r.paths = append(r.paths, f)
+		} else {
+			r.names = append(r.names, f)
+		}
+	}
+
+	redaction.Store(r)
+}
+
+type redactor struct {
+	mode  Redaction
+	key   []byte
+	names []string
+	paths []string
+}
+
+// match tells whether the field fd, at path from the event, is
+// sensitive.
+func (r *redactor) match(fd protoreflect.FieldDescriptor, path string) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if ok && opts.GetDebugRedact() {
+		return true
+	}
+
+	for _, name := range r.names {
+		if string(fd.Name()) == name {
+			return true
+		}
+	}
+
+	for _, p := range r.paths {
+		if p == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// field returns the redacted form of the field fd. Lists and maps keep
+// their length and keys.
+func (r *redactor) field(fd protoreflect.FieldDescriptor,
+	v protoreflect.Value) any {
+	switch {
+	case fd.IsList():
+		arr := make([]any, 0, v.List().Len())
+		for i := 0; i < v.List().Len(); i++ {
+			arr = append(arr, r.value(v.List().Get(i)))
+		}
+		return arr
+
+	case fd.IsMap():
+		tmp := make(map[string]any)
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			tmp[k.String()] = r.value(v)
+			return true
+		})
+		return tmp
+
+	default:
+		return r.value(v)
+	}
+}
+
+// value returns the mask or hash of a single value.
+func (r *redactor) value(v protoreflect.Value) string {
+	if r.mode != RedactHash || len(r.key) == 0 {
+		return RedactedMask
+	}
+
+	var raw []byte
+	switch x := v.Interface().(type) {
+	case []byte:
+		raw = x
+	case string:
+		raw = []byte(x)
+	case protoreflect.Message:
+		raw, _ = proto.MarshalOptions{Deterministic: true}.Marshal(
+			x.Interface())
+	default:
+		raw = fmt.Append(nil, x)
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write(raw)
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const accountProto = `
+name: "zlog_test.proto"
+package: "zlog.test"
+syntax: "proto3"
+message_type {
+  name: "Profile"
+  field { name: "email" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field {
+    name: "secret" number: 2 type: TYPE_BYTES label: LABEL_OPTIONAL
+    options { debug_redact: true }
+  }
+}
+message_type {
+  name: "Account"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+  field {
+    name: "password" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL
+    options { debug_redact: true }
+  }
+  field {
+    name: "profile" number: 3 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+    type_name: ".zlog.test.Profile"
+  }
+  field {
+    name: "history" number: 4 type: TYPE_MESSAGE label: LABEL_REPEATED
+    type_name: ".zlog.test.Profile"
+  }
+  field {
+    name: "by_id" number: 5 type: TYPE_MESSAGE label: LABEL_REPEATED
+    type_name: ".zlog.test.Account.ByIdEntry"
+  }
+  field { name: "tokens" number: 6 type: TYPE_STRING label: LABEL_REPEATED }
+  nested_type {
+    name: "ByIdEntry"
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL }
+    field {
+      name: "value" number: 2 type: TYPE_MESSAGE label: LABEL_OPTIONAL
+      type_name: ".zlog.test.Profile"
+    }
+    options { map_entry: true }
+  }
+}
+`
+
+const accountText = `
+name: "alice"
+password: "hunter2"
+profile { email: "alice@example.com" secret: "s1" }
+history { email: "old@example.com" }
+by_id { key: "a" value { email: "a@example.com" secret: "s2" } }
+tokens: "t1"
+tokens: "t2"
+`
+
+// newAccount returns an Account event with sensitive fields at every
+// depth.
+func newAccount(t *testing.T) proto.Message {
+	fdp := &descriptorpb.FileDescriptorProto{}
+	require.NoError(t, prototext.Unmarshal([]byte(accountProto), fdp))
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(fd.Messages().ByName("Account"))
+	require.NoError(t, prototext.Unmarshal([]byte(accountText), msg))
+	return msg
+}
+
+// restoreRedaction resets the redaction after the test.
+func restoreRedaction(t *testing.T) {
+	prev := redaction.Load()
+	t.Cleanup(func() { redaction.Store(prev) })
+}
+
+func marshalFields(t *testing.T, msg proto.Message) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	require.NoError(t, marshalProto(enc, msg))
+	return enc.Fields
+}
+
+func TestRedactDebugRedact(t *testing.T) {
+	restoreRedaction(t)
+	SetRedaction(RedactMask, nil)
+
+	fields := marshalFields(t, newAccount(t))
+	assert.Equal(t, "alice", fields["name"])
+	assert.Equal(t, RedactedMask, fields["password"])
+	assert.Equal(t, map[string]any{
+		"email":  "alice@example.com",
+		"secret": RedactedMask,
+	}, fields["profile"])
+	assert.Equal(t, map[string]any{
+		"a": map[string]any{"email": "a@example.com", "secret": RedactedMask},
+	}, fields["byId"])
+	assert.Equal(t, []any{"t1", "t2"}, fields["tokens"])
+}
+
+func TestRedactFields(t *testing.T) {
+	restoreRedaction(t)
+	SetRedaction(RedactMask, nil, "email", "tokens", "by_id.secret")
+
+	fields := marshalFields(t, newAccount(t))
+	assert.Equal(t, map[string]any{
+		"email":  RedactedMask,
+		"secret": RedactedMask,
+	}, fields["profile"])
+	assert.Equal(t, []any{map[string]any{"email": RedactedMask}},
+		fields["history"])
+	assert.Equal(t, []any{RedactedMask, RedactedMask}, fields["tokens"])
+
+	SetRedaction(RedactMask, nil, "profile.email")
+	fields = marshalFields(t, newAccount(t))
+	assert.Equal(t, RedactedMask,
+		fields["profile"].(map[string]any)["email"])
+	assert.Equal(t, []any{map[string]any{"email": "old@example.com"}},
+		fields["history"])
+}
+
+func TestRedactHash(t *testing.T) {
+	restoreRedaction(t)
+	SetRedaction(RedactHash, []byte("k1"), "name", "tokens")
+
+	fields := marshalFields(t, newAccount(t))
+	again := marshalFields(t, newAccount(t))
+
+	mac := hmac.New(sha256.New, []byte("k1"))
+	mac.Write([]byte("alice"))
+	assert.Equal(t, "hmac:"+hex.EncodeToString(mac.Sum(nil)[:8]),
+		fields["name"])
+	assert.Equal(t, fields["name"], again["name"])
+	assert.NotEqual(t, fields["name"], fields["password"])
+
+	tokens := fields["tokens"].([]any)
+	require.Len(t, tokens, 2)
+	assert.NotEqual(t, tokens[0], tokens[1])
+
+	SetRedaction(RedactHash, []byte("k2"), "name")
+	assert.NotEqual(t, fields["name"], marshalFields(t, newAccount(t))["name"])
+
+	SetRedaction(RedactHash, nil, "name")
+	assert.Equal(t, RedactedMask, marshalFields(t, newAccount(t))["name"])
+}
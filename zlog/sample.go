@@ -0,0 +1,237 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sampling holds the sampler of all loggers, nil when disabled.
+var sampling atomic.Pointer[sampler]
+
+// Sampling limits repeated log lines. Lines are keyed by logger scope,
+// level and message template, e.g. the format of Warnf or the first arg
+// of Warning, so that "channel for names %s is full" is one key whatever
+// the names. In every Interval, the First lines of a key are logged, then
+// every Thereafter-th one. Fatal lines are never sampled. A Sampling with
+// neither First nor Thereafter set would drop every line, so it disables
+// sampling instead.
+type Sampling struct {
+	// Interval is the sampling window, a second when zero.
+	Interval time.Duration
+
+	// First is the number of lines of a key logged per Interval.
+	First int
+
+	// Thereafter logs every Thereafter-th line past First, none when
+	// zero.
+	Thereafter int
+
+	// Summary is how often the number of suppressed lines of each key is
+	// logged, a minute when zero.
+	Summary time.Duration
+}
+
+// SetSampling enables sampling for all loggers, or disables it when s is
+// nil or zero, see Sampling. Suppressed lines are counted and reported,
+// per key, in a summary line logged by the logger of the scope, without
+// the fields added by With or Context.
+func SetSampling(s *Sampling) {
+	var next *sampler
+	if s != nil && (s.First > 0 || s.Thereafter > 0) {
+		next = newSampler(*s, time.Now)
+		next.start()
+	}
+
+	if prev := sampling.Swap(next); prev != nil {
+		prev.stop()
+	}
+}
+
+// sampleKey identifies repeated lines.
+type sampleKey struct {
+	scope    string
+	level    Level
+	template string
+}
+
+// sampleCount counts the lines of a key.
+type sampleCount struct {
+	log        *zap.Logger
+	window     time.Time
+	n          int
+	suppressed int
+}
+
+type sampler struct {
+	cfg  Sampling
+	now  func() time.Time
+	done chan struct{}
+
+	mu     sync.Mutex
+	counts map[sampleKey]*sampleCount
+}
+
+func newSampler(cfg Sampling, now func() time.Time) *sampler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	if cfg.Summary <= 0 {
+		cfg.Summary = time.Minute
+	}
+
+	return &sampler{
+		cfg:    cfg,
+		now:    now,
+		done:   make(chan struct{}),
+		counts: map[sampleKey]*sampleCount{},
+	}
+}
+
+// start logs the summaries until stop.
+func (s *sampler) start() {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Summary)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.summarize()
+			case <-s.done:
+				s.summarize()
+				return
+			}
+		}
+	}()
+}
+
+func (s *sampler) stop() {
+	close(s.done)
+}
+
+// allow tells whether a line of key is logged. log is the logger of the
+// scope of key, reporting the lines suppressed.
+func (s *sampler) allow(key sampleKey, log *zap.Logger) bool {
+	if key.level >= LevelFatal {
+		return true
+	}
+
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counts[key]
+	if !ok {
+		c = &sampleCount{log: log}
+		s.counts[key] = c
+	}
+
+	if now.Sub(c.window) >= s.cfg.Interval {
+		c.window, c.n = now, 0
+	}
+	c.n++
+
+	if c.n <= s.cfg.First ||
+		s.cfg.Thereafter > 0 && (c.n-s.cfg.First)%s.cfg.Thereafter == 0 {
+		return true
+	}
+
+	c.suppressed++
+	return false
+}
+
+// summary is the summary line of a key.
+type summary struct {
+	log        *zap.Logger
+	key        sampleKey
+	suppressed int
+}
+
+// summarize logs the lines suppressed since the last summary and drops
+// the keys idle for a whole interval. The lines are logged after the
+// counts are unlocked, so that sampled calls do not wait on the sinks.
+func (s *sampler) summarize() {
+	for _, sum := range s.summaries() {
+		sum.log.WithOptions(zap.WithCaller(false)).Log(sum.key.level.Zap(),
+			fmt.Sprintf("suppressed %d lines like %q", sum.suppressed,
+				sum.key.template),
+			zap.Int("suppressed", sum.suppressed),
+			zap.Duration("interval", s.cfg.Summary))
+	}
+}
+
+// summaries returns the summaries to log and resets their counts.
+func (s *sampler) summaries() []summary {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []summary
+	for key, c := range s.counts {
+		if c.suppressed > 0 {
+			out = append(out, summary{c.log, key, c.suppressed})
+			c.suppressed = 0
+			continue
+		}
+
+		if now.Sub(c.window) >= s.cfg.Interval {
+			delete(s.counts, key)
+		}
+	}
+
+	return out
+}
+
+// sampled tells whether a line of scope at ll passes sampling, when
+// enabled.
+func sampled(scope string, ll Level, template string,
+	log *zap.Logger) bool {
+	s := sampling.Load()
+	if s == nil {
+		return true
+	}
+
+	return s.allow(sampleKey{scope: scope, level: ll, template: template}, log)
+}
+
+// sampleArgs is sampled for lines logged without a template, keyed by
+// their first arg: the literal message, e.g. "published" of
+// Info("published", n), or the type of a non-string first arg, so that
+// the key does not depend on the values logged.
+func sampleArgs(scope string, ll Level, args []any, log *zap.Logger) bool {
+	if sampling.Load() == nil {
+		return true
+	}
+
+	var template string
+	if len(args) > 0 {
+		if s, ok := args[0].(string); ok {
+			template = s
+		} else {
+			template = fmt.Sprintf("%T", args[0])
+		}
+	}
+
+	return sampled(scope, ll, template, log)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// useSampler makes s the sampler of all loggers during the test.
+func useSampler(t *testing.T, s *sampler) {
+	prev := sampling.Swap(s)
+	t.Cleanup(func() { sampling.Store(prev) })
+}
+
+func TestSampling(t *testing.T) {
+	restoreScopes(t)
+	clock := newFakeClock()
+	useSampler(t, newSampler(Sampling{First: 2, Thereafter: 3}, clock.now))
+
+	c, logs := observedSugar("eventq", LevelDebug)
+	for i := range 10 {
+		c.Warningf("channel for names %d is full, dropping event", i)
+	}
+	c.Infof("published event to names: %d", 1)
+
+	var got []string
+	for _, e := range logs.TakeAll() {
+		got = append(got, e.Message)
+	}
+	assert.Equal(t, []string{
+		"channel for names 0 is full, dropping event",
+		"channel for names 1 is full, dropping event",
+		"channel for names 4 is full, dropping event",
+		"channel for names 7 is full, dropping event",
+		"published event to names: 1",
+	}, got)
+
+	clock.advance(time.Second)
+	c.Warningf("channel for names %d is full, dropping event", 10)
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestSamplingArgs(t *testing.T) {
+	restoreScopes(t)
+	s := newSampler(Sampling{First: 2, Thereafter: 3}, newFakeClock().now)
+	useSampler(t, s)
+
+	c, logs := observedSugar("auth", LevelDebug)
+	for i := range 5 {
+		c.Info("user ", i, " signed in")
+	}
+	for _, msg := range []string{"a", "b", "c"} {
+		c.Error(errors.New(msg))
+	}
+
+	var got []string
+	for _, e := range logs.TakeAll() {
+		got = append(got, e.Message)
+	}
+	assert.Equal(t, []string{
+		"user 0 signed in", "user 1 signed in", "user 4 signed in", "a", "b",
+	}, got)
+	assert.Len(t, s.counts, 2)
+}
+
+func TestSamplingSummary(t *testing.T) {
+	restoreScopes(t)
+	clock := newFakeClock()
+	s := newSampler(Sampling{First: 2, Thereafter: 3}, clock.now)
+	useSampler(t, s)
+
+	c, logs := observedSugar("eventq", LevelDebug)
+	job := c.With("job", "j1")
+	for i := range 10 {
+		job.Warningf("channel for names %d is full, dropping event", i)
+	}
+	for range 3 {
+		c.Info("published")
+	}
+	logs.TakeAll()
+
+	s.summarize()
+	summaries := logs.TakeAll()
+	require.Len(t, summaries, 2)
+	want := map[zapcore.Level]string{
+		zapcore.WarnLevel: `suppressed 6 lines like ` +
+			`"channel for names %d is full, dropping event"`,
+		zapcore.InfoLevel: `suppressed 1 lines like "published"`,
+	}
+	for _, e := range summaries {
+		assert.Equal(t, want[e.Level], e.Message)
+		assert.Equal(t, "eventq", e.LoggerName)
+		assert.NotContains(t, e.ContextMap(), "job")
+	}
+
+	clock.advance(time.Second)
+	s.summarize()
+	assert.Zero(t, logs.Len())
+	assert.Empty(t, s.counts)
+}
+
+func TestSamplingSummaryUnlocked(t *testing.T) {
+	s := newSampler(Sampling{First: 1}, newFakeClock().now)
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core, zap.Hooks(func(zapcore.Entry) error {
+		s.allow(sampleKey{scope: "other"}, zap.NewNop())
+		return nil
+	}))
+
+	key := sampleKey{scope: "eventq", level: LevelInfo, template: "m"}
+	assert.True(t, s.allow(key, log))
+	assert.False(t, s.allow(key, log))
+
+	done := make(chan struct{})
+	go func() {
+		s.summarize()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("summarize logs while holding the counts")
+	}
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestSamplingLogger(t *testing.T) {
+	restoreScopes(t)
+	useSampler(t, newSampler(Sampling{First: 1}, newFakeClock().now))
+
+	l, logs := observedSugar("", LevelDebug)
+	ll := &logger{log: l.base, base: l.base, level: l.level}
+	for range 3 {
+		ll.Error("dropped event", wrapperspb.String("e"))
+	}
+	assert.Equal(t, 1, logs.Len())
+
+	s := sampling.Load()
+	assert.True(t, s.allow(sampleKey{level: LevelFatal}, nil))
+	assert.True(t, s.allow(sampleKey{level: LevelFatal}, nil))
+}
+
+func TestSetSampling(t *testing.T) {
+	useSampler(t, nil)
+
+	SetSampling(&Sampling{First: 1})
+	s := sampling.Load()
+	require.NotNil(t, s)
+	assert.Equal(t, time.Second, s.cfg.Interval)
+	assert.Equal(t, time.Minute, s.cfg.Summary)
+
+	SetSampling(&Sampling{Interval: time.Second})
+	assert.Nil(t, sampling.Load())
+
+	SetSampling(nil)
+	assert.Nil(t, sampling.Load())
+	assert.True(t, sampled("", LevelInfo, "any", nil))
+
+	select {
+	case <-s.done:
+	default:
+		t.Error("previous sampler not stopped")
+	}
+}
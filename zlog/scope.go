@@ -0,0 +1,204 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// anyScope is the spec scope matching loggers without a specific rule.
+const anyScope = "*"
+
+// scopes holds the levels of the named loggers.
+var scopes = &registry{levels: map[string]zap.AtomicLevel{}}
+
+// Spec maps logger scopes to levels, e.g. eventq=warn,db=debug,*=info.
+// A rule for a scope also covers its dotted children, so eventq matches
+// eventq.publish. The * rule matches every other logger and sets the
+// package console level.
+type Spec map[string]Level
+
+// ParseSpec parses comma-separated scope=level rules. A bare level is
+// the * rule, so a plain level name is a valid spec.
+func ParseSpec(s string) (Spec, error) {
+	spec := Spec{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		scope, name, ok := strings.Cut(part, "=")
+		if !ok {
+			scope, name = anyScope, part
+		}
+
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			return nil, fmt.Errorf("invalid level spec %q: empty scope", part)
+		}
+
+		ll, err := ParseLevel(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid level spec %q: %w", part, err)
+		}
+		spec[scope] = ll
+	}
+
+	return spec, nil
+}
+
+// String renders the spec with the * rule first, as a bare level, and
+// the other rules sorted by scope.
+func (s Spec) String() string {
+	var parts []string
+	if ll, ok := s[anyScope]; ok {
+		parts = append(parts, ll.String())
+	}
+
+	for _, scope := range slices.Sorted(maps.Keys(s)) {
+		if scope != anyScope {
+			parts = append(parts, scope+"="+s[scope].String())
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// match returns the level of the most specific rule covering scope.
+func (s Spec) match(scope string) (Level, bool) {
+	if ll, ok := s.rule(scope); ok {
+		return ll, true
+	}
+
+	ll, ok := s[anyScope]
+	return ll, ok
+}
+
+// rule returns the level of the rule of scope or of its closest dotted
+// parent, leaving out the * rule.
+func (s Spec) rule(scope string) (Level, bool) {
+	for name := scope; name != ""; {
+		if ll, ok := s[name]; ok {
+			return ll, true
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	return 0, false
+}
+
+// childScope returns the scope of the child name of parent.
+func childScope(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// SetLevelSpec applies a level spec, e.g. the value of flagx's
+// --log_level, which falls back to SENZ_LOG_LEVEL. Existing loggers
+// covered by a rule change level at once, loggers created later start at
+// their matching rule. Loggers no rule covers keep their level. The
+// package console follows the rule of its scope, see SetScopeLogLevel.
+func SetLevelSpec(s string) error {
+	spec, err := ParseSpec(s)
+	if err != nil {
+		return err
+	}
+
+	scopes.apply(spec)
+	return nil
+}
+
+// LevelSpec returns the applied spec, with the * rule reporting the
+// current package console level.
+func LevelSpec() string {
+	spec := scopes.current()
+	spec[anyScope] = GetLogLevel()
+	return spec.String()
+}
+
+// registry shares one level per scope between the loggers of that scope.
+type registry struct {
+	mu     sync.Mutex
+	spec   Spec
+	levels map[string]zap.AtomicLevel
+}
+
+// level returns the level of scope. A new scope starts at its matching
+// rule, or at def without one.
+func (r *registry) level(scope string, def Level) zap.AtomicLevel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.levels[scope]; ok {
+		return l
+	}
+
+	if ll, ok := r.spec.match(scope); ok {
+		def = ll
+	}
+
+	l := zap.NewAtomicLevelAt(def.Zap())
+	r.levels[scope] = l
+	return l
+}
+
+// lookup returns the level of an existing scope.
+func (r *registry) lookup(scope string) (zap.AtomicLevel, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, ok := r.levels[scope]
+	return l, ok
+}
+
+func (r *registry) apply(spec Spec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spec = spec
+	for scope, l := range r.levels {
+		if ll, ok := spec.match(scope); ok {
+			l.SetLevel(ll.Zap())
+		}
+	}
+
+	if ll, ok := spec.match(console.scope); ok {
+		SetLogLevel(ll)
+	}
+}
+
+func (r *registry) current() Spec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spec := Spec{}
+	maps.Copy(spec, r.spec)
+	return spec
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// restoreScopes resets the scope registry after the test.
+func restoreScopes(t *testing.T) {
+	restoreLevel(t)
+
+	prev := scopes
+	scopes = &registry{levels: map[string]zap.AtomicLevel{}}
+	t.Cleanup(func() { scopes = prev })
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr string
+	}{
+		{in: "info", want: Spec{"*": LevelInfo}},
+		{
+			in: "eventq=warn, db=debug,*=info",
+			want: Spec{
+				"eventq": LevelWarning,
+				"db":     LevelDebug,
+				"*":      LevelInfo,
+			},
+		},
+		{in: "", want: Spec{}},
+		{in: "db=loud", wantErr: `invalid level spec "db=loud"`},
+		{in: "=info", wantErr: "empty scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSpec(tt.in)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSpecString(t *testing.T) {
+	spec := Spec{"eventq": LevelWarning, "db": LevelDebug, "*": LevelInfo}
+	assert.Equal(t, "info,db=debug,eventq=warning", spec.String())
+}
+
+func TestSetLevelSpec(t *testing.T) {
+	restoreScopes(t)
+
+	eventq := NewConsole("eventq", LevelDebug)
+	other := NewConsole("other", LevelDebug)
+
+	require.NoError(t, SetLevelSpec("eventq=warn,db=debug,*=info"))
+	assert.False(t, eventq.V(LevelInfo.Int()))
+	assert.True(t, eventq.V(LevelWarning.Int()))
+	assert.False(t, other.V(LevelDebug.Int()), "* rule")
+	assert.Equal(t, LevelInfo, GetLogLevel())
+
+	db := NewConsole("db", LevelError)
+	assert.True(t, db.V(LevelDebug.Int()), "rule over constructor level")
+
+	child := NewJSONLogger("eventq.publish", 0, LevelDebug)
+	assert.False(t, child.V(LevelInfo.Int()), "parent scope rule")
+
+	same := NewConsole("eventq", LevelDebug)
+	same.Level().SetLevel(LevelError.Zap())
+	assert.False(t, eventq.V(LevelWarning.Int()), "scope shares its level")
+
+	assert.Equal(t, "info,db=debug,eventq=warning", LevelSpec())
+	assert.Error(t, SetLevelSpec("eventq=loud"))
+}
+
+func TestLevelHandlerScope(t *testing.T) {
+	restoreScopes(t)
+	c := NewConsole("eventq", LevelInfo)
+
+	rec := httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/?scope=eventq", nil))
+	assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut,
+		"/?scope=eventq", strings.NewReader(`{"level":"error"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, c.V(LevelWarning.Int()))
+
+	rec = httptest.NewRecorder()
+	LevelHandler().ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/?scope=nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSetScopeLogLevel(t *testing.T) {
+	restoreScopes(t)
+	prev := console
+	t.Cleanup(func() { console = prev })
+
+	require.NoError(t, SetLevelSpec("warn,cron=debug"))
+	SetScopeLogLevel("cron.worker", LevelError)
+	assert.Equal(t, LevelDebug, GetLogLevel(), "scope rule over level")
+
+	require.NoError(t, SetLevelSpec("warn,cron=info"))
+	assert.Equal(t, LevelInfo, GetLogLevel(), "spec follows console scope")
+
+	SetScopeLogLevel("api", LevelError)
+	assert.Equal(t, LevelError, GetLogLevel(), "* rule under level")
+}
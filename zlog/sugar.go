@@ -15,7 +15,7 @@
 package zlog
 
 import (
-	"github.com/sentinez/shared/color"
+	"context"
 
 	"go.uber.org/zap"
 )
@@ -45,96 +45,121 @@ type Sugar interface {
 	Fatalln(args ...any)
 
 	V(l int) bool
+	Level() zap.AtomicLevel
+	Context(ctx context.Context) Sugar
+	With(args ...any) Sugar
+	Named(name string) Sugar
 	Sync() error
 }
 
-func NewConsole(scope string, level Level) Sugar {
-	scope = color.Green.Add(scope)
-	log := configConsoleLogger(scope).Sugar()
-	return createSugard(log, ToLevel(level.String()).Int())
+// NewConsole creates a console logger named scope, writing to sinks or
+// Stdout without any. Loggers of the same scope share their level, which
+// starts at the matching SetLevelSpec rule, or at level without one.
+func NewConsole(scope string, level Level, sinks ...Sink) Sugar {
+	atomic := scopes.level(scope, level)
+	return newConsole(scope, atomic, sinks...)
 }
 
-func NewDefaultConsole(level Level) Sugar {
-	log := configConsoleLogger("").Sugar()
-	return createSugard(log, ToLevel(level.String()).Int())
+func NewDefaultConsole(level Level, sinks ...Sink) Sugar {
+	return newConsole("", zap.NewAtomicLevelAt(level.Zap()), sinks...)
+}
+
+func newConsole(scope string, level zap.AtomicLevel, sinks ...Sink) *sugard {
+	log := configConsoleLogger(scope, level, sinks...)
+	return &sugard{scope: scope, level: level, Logger: log.Sugar(), base: log}
 }
 
 // sugard is the logger for the package.
 type sugard struct {
-	Verbosity int
-	Logger    *zap.SugaredLogger
+	scope  string
+	level  zap.AtomicLevel
+	Logger *zap.SugaredLogger
+
+	// base is the logger of the scope, without the fields of With and
+	// Context. It logs the sampling summaries.
+	base *zap.Logger
 }
 
 // Debug logs a debug message.
 func (c *sugard) Debug(args ...any) {
-	if c.V(LevelDebug.Int()) {
+	if c.V(LevelDebug.Int()) && c.sampleArgs(LevelDebug, args) {
 		c.Logger.Debug(args...)
 	}
 }
 
-// Debugln logs a debug message.
-func (c *sugard) Debugln(args ...any) { c.Debug(args...) }
+// Debugln logs a debug message, spacing args as fmt.Sprintln.
+func (c *sugard) Debugln(args ...any) {
+	if c.V(LevelDebug.Int()) && c.sampleArgs(LevelDebug, args) {
+		c.Logger.Debugln(args...)
+	}
+}
 
 // Debugf logs a debug message with a format.
 func (c *sugard) Debugf(format string, args ...any) {
-	if c.V(LevelDebug.Int()) {
+	if c.V(LevelDebug.Int()) && c.sample(LevelDebug, format) {
 		c.Logger.Debugf(format, args...)
 	}
 }
 
 // Info logs an info message.
 func (c *sugard) Info(args ...any) {
-	if c.V(LevelInfo.Int()) {
+	if c.V(LevelInfo.Int()) && c.sampleArgs(LevelInfo, args) {
 		c.Logger.Info(args...)
 	}
 }
 
-// Infoln logs an info message.
+// Infoln logs an info message, spacing args as fmt.Sprintln.
 func (c *sugard) Infoln(args ...any) {
-	c.Info(args...)
+	if c.V(LevelInfo.Int()) && c.sampleArgs(LevelInfo, args) {
+		c.Logger.Infoln(args...)
+	}
 }
 
 // Infof logs an info message with a format.
 func (c *sugard) Infof(format string, args ...any) {
-	if c.V(LevelInfo.Int()) {
+	if c.V(LevelInfo.Int()) && c.sample(LevelInfo, format) {
 		c.Logger.Infof(format, args...)
 	}
 }
 
 // Warning logs a warning message.
 func (c *sugard) Warning(args ...any) {
-	if c.V(LevelWarning.Int()) {
+	if c.V(LevelWarning.Int()) && c.sampleArgs(LevelWarning, args) {
 		c.Logger.Warn(args...)
 	}
 }
 
-// Warningln logs a warning message.
+// Warningln logs a warning message, spacing args as fmt.Sprintln.
 func (c *sugard) Warningln(args ...any) {
-	c.Warning(args...)
+	if c.V(LevelWarning.Int()) && c.sampleArgs(LevelWarning, args) {
+		c.Logger.Warnln(args...)
+	}
 }
 
 // Warningf logs a warning message with a format.s
 func (c *sugard) Warningf(format string, args ...any) {
-	if c.V(LevelWarning.Int()) {
+	if c.V(LevelWarning.Int()) && c.sample(LevelWarning, format) {
 		c.Logger.Warnf(format, args...)
 	}
 }
 
 // Error logs an error message.
 func (c *sugard) Error(args ...any) {
-	if c.V(LevelError.Int()) {
+	if c.V(LevelError.Int()) && c.sampleArgs(LevelError, args) {
 		c.Logger.Error(args...)
 	}
 }
 
-// Errorln logs an error message.
+// Errorln logs an error message, spacing args as fmt.Sprintln.
 func (c *sugard) Errorln(args ...any) {
-	c.Error(args...)
+	if c.V(LevelError.Int()) && c.sampleArgs(LevelError, args) {
+		c.Logger.Errorln(args...)
+	}
 }
 
 // Errorf logs an error message with a format.
 func (c *sugard) Errorf(format string, args ...any) {
-	if c.V(LevelError.Int()) {
+	if c.V(LevelError.Int()) && c.sample(LevelError, format) {
 		c.Logger.Errorf(format, args...)
 	}
 }
@@ -146,8 +171,12 @@ func (c *sugard) Fatal(args ...any) {
 	}
 }
 
-// Fatalln logs a fatal message.
-func (c *sugard) Fatalln(args ...any) { c.Fatal(args...) }
+// Fatalln logs a fatal message, spacing args as fmt.Sprintln.
+func (c *sugard) Fatalln(args ...any) {
+	if c.V(LevelFatal.Int()) {
+		c.Logger.Fatalln(args...)
+	}
+}
 
 // Fatalf logs a fatal message with a format.
 func (c *sugard) Fatalf(format string, args ...any) {
@@ -156,9 +185,62 @@ func (c *sugard) Fatalf(format string, args ...any) {
 	}
 }
 
+// sample tells whether a line at ll logged with template passes
+// sampling, see SetSampling.
+func (c *sugard) sample(ll Level, template string) bool {
+	return sampled(c.scope, ll, template, c.base)
+}
+
+// sampleArgs is sample for lines logged without a template.
+func (c *sugard) sampleArgs(ll Level, args []any) bool {
+	return sampleArgs(c.scope, ll, args, c.base)
+}
+
 // V reports whether verbosity level l is at least the requested verbose level.
 func (c *sugard) V(l int) bool {
-	return l >= c.Verbosity
+	return c.level.Enabled(Level(l).Zap())
+}
+
+// Level returns the level of the logger, which can be changed at any time.
+func (c *sugard) Level() zap.AtomicLevel {
+	return c.level
+}
+
+// Context returns the logger with the fields of ctx, see WithContext.
+func (c *sugard) Context(ctx context.Context) Sugar {
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return c
+	}
+
+	return c.with(c.Logger.Desugar().With(fields...).Sugar())
+}
+
+// With returns a child logger adding args to every line. Like zap's
+// SugaredLogger.With, args are zap fields or loosely typed key-value
+// pairs, e.g. With("component", "cron").
+func (c *sugard) With(args ...any) Sugar {
+	if len(args) == 0 {
+		return c
+	}
+
+	return c.with(c.Logger.With(args...))
+}
+
+// Named returns a child logger of the scope name below the logger's
+// own, e.g. cron.cleanup. The child has its own level, which starts at
+// the matching SetLevelSpec rule, or at the current level of c.
+func (c *sugard) Named(name string) Sugar {
+	scope := childScope(c.scope, name)
+	level := scopes.level(scope, fromZap(c.level.Level()))
+	log := withLevel(c.Logger.Desugar().Named(name), level)
+	base := withLevel(c.base.Named(name), level)
+	return &sugard{scope: scope, level: level, Logger: log.Sugar(), base: base}
+}
+
+// with returns a logger of the same scope and level as c writing to log.
+func (c *sugard) with(log *zap.SugaredLogger) *sugard {
+	return &sugard{scope: c.scope, level: c.level, Logger: log, base: c.base}
 }
 
 // Sync flushes the log.
@@ -167,6 +249,6 @@ func (c *sugard) Sync() error {
 }
 
 // createSugard creates a new Core.
-func createSugard(logger *zap.SugaredLogger, verbosity int) *sugard {
-	return &sugard{Logger: logger, Verbosity: verbosity}
+func createSugard(logger *zap.SugaredLogger, level zap.AtomicLevel) *sugard {
+	return &sugard{Logger: logger, level: level, base: logger.Desugar()}
 }
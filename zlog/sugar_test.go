@@ -0,0 +1,140 @@
+// Copyright 2025 Duc-Hung Ho.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zlog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observedSugar returns a console of scope writing to an observer, with
+// the caller options of configConsoleLogger.
+func observedSugar(scope string, ll Level) (*sugard, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)).Named(scope)
+	return &sugard{
+		scope:  scope,
+		level:  scopes.level(scope, ll),
+		Logger: log.Sugar(),
+		base:   log,
+	}, logs
+}
+
+func TestSugarWith(t *testing.T) {
+	restoreScopes(t)
+	c, logs := observedSugar("cron", LevelInfo)
+	assert.Same(t, c, c.With())
+
+	job := c.With("job", "cleanup", zap.Int("attempt", 2))
+	job.Infof("ran %d rows", 3)
+	job.Debug("dropped")
+	c.Info("parent")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ran 3 rows", entries[0].Message)
+	assert.Equal(t, map[string]any{"job": "cleanup", "attempt": int64(2)},
+		entries[0].ContextMap())
+	assert.Equal(t, "sugar_test.go", filepath.Base(entries[0].Caller.File))
+	assert.Empty(t, entries[1].Context)
+
+	assert.Equal(t, c.Level(), job.Level())
+	c.Level().SetLevel(zap.DebugLevel)
+	assert.True(t, job.V(LevelDebug.Int()))
+}
+
+func TestSugarNamed(t *testing.T) {
+	restoreScopes(t)
+	require.NoError(t, SetLevelSpec("cron.cleanup=debug"))
+	c, logs := observedSugar("cron", LevelWarning)
+
+	cleanup := c.With("component", "cron").Named("cleanup")
+	cleanup.Debug("verbose child")
+	c.Info("quiet parent")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "verbose child", entries[0].Message)
+	assert.Contains(t, entries[0].LoggerName, "cleanup")
+	assert.Equal(t, "cron", entries[0].ContextMap()["component"])
+	assert.Equal(t, "sugar_test.go", filepath.Base(entries[0].Caller.File))
+
+	sync := c.Named("sync")
+	assert.Equal(t, zap.WarnLevel, sync.Level().Level())
+	sync.Info("dropped")
+	assert.Equal(t, 1, logs.Len())
+
+	require.NoError(t, SetLevelSpec("cron=error"))
+	assert.False(t, cleanup.V(LevelWarning.Int()))
+	assert.False(t, sync.V(LevelWarning.Int()))
+}
+
+// sugarMethods logs a line with every logging method of Sugar.
+var sugarMethods = map[string]func(Sugar){
+	"Debug":     func(s Sugar) { s.Debug("m") },
+	"Debugf":    func(s Sugar) { s.Debugf("m") },
+	"Debugln":   func(s Sugar) { s.Debugln("m") },
+	"Info":      func(s Sugar) { s.Info("m") },
+	"Infof":     func(s Sugar) { s.Infof("m") },
+	"Infoln":    func(s Sugar) { s.Infoln("m") },
+	"Warning":   func(s Sugar) { s.Warning("m") },
+	"Warningf":  func(s Sugar) { s.Warningf("m") },
+	"Warningln": func(s Sugar) { s.Warningln("m") },
+	"Error":     func(s Sugar) { s.Error("m") },
+	"Errorf":    func(s Sugar) { s.Errorf("m") },
+	"Errorln":   func(s Sugar) { s.Errorln("m") },
+	"Fatal":     func(s Sugar) { s.Fatal("m") },
+	"Fatalf":    func(s Sugar) { s.Fatalf("m") },
+	"Fatalln":   func(s Sugar) { s.Fatalln("m") },
+}
+
+func TestSugarCaller(t *testing.T) {
+	restoreScopes(t)
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1),
+		zap.WithFatalHook(zapcore.WriteThenPanic))
+	c := &sugard{
+		scope:  "caller",
+		level:  scopes.level("caller", LevelDebug),
+		Logger: log.Sugar(),
+		base:   log,
+	}
+
+	children := map[string]Sugar{
+		"With":  c.With("k", "v"),
+		"Named": c.Named("child"),
+	}
+	for child, s := range children {
+		for name, method := range sugarMethods {
+			if strings.HasPrefix(name, "Fatal") {
+				assert.Panics(t, func() { method(s) })
+			} else {
+				method(s)
+			}
+
+			entries := logs.TakeAll()
+			require.Len(t, entries, 1, "%s.%s", child, name)
+			assert.Equal(t, "sugar_test.go",
+				filepath.Base(entries[0].Caller.File), "%s.%s", child, name)
+		}
+	}
+}